@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ADB runs adb commands against the connected robot. It is an interface so tests can use a fake device.
+type ADB interface {
+	Run(args ...string) ([]byte, error)
+}
+
+// execADB runs the adb binary, optionally against one device serial.
+type execADB struct {
+	path   string
+	serial string
+}
+
+func (a execADB) Run(args ...string) ([]byte, error) {
+	if a.serial != "" {
+		args = append([]string{"-s", a.serial}, args...)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(a.path, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return out, fmt.Errorf("adb %s: %s", args[0], msg)
+		}
+		return out, fmt.Errorf("adb %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// findADB locates adb: adb_path, then the Android SDK from ANDROID_HOME / ANDROID_SDK_ROOT or
+// Android Studio's default SDK location, then PATH.
+func findADB() (string, error) {
+	if p := viper.GetString("adb_path"); p != "" {
+		if _, err := os.Stat(p); err != nil {
+			return "", fmt.Errorf("adb_path %s: %w", p, err)
+		}
+		return p, nil
+	}
+
+	exe := "adb"
+	if runtime.GOOS == "windows" {
+		exe = "adb.exe"
+	}
+	var sdks []string
+	for _, env := range []string{"ANDROID_HOME", "ANDROID_SDK_ROOT"} {
+		if d := os.Getenv(env); d != "" {
+			sdks = append(sdks, d)
+		}
+	}
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		sdks = append(sdks, filepath.Join(os.Getenv("LOCALAPPDATA"), "Android", "Sdk"))
+	case "darwin":
+		sdks = append(sdks, filepath.Join(home, "Library", "Android", "sdk"))
+	default:
+		sdks = append(sdks, filepath.Join(home, "Android", "Sdk"))
+	}
+	for _, sdk := range sdks {
+		p := filepath.Join(sdk, "platform-tools", exe)
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+
+	if p, err := exec.LookPath(exe); err == nil {
+		return p, nil
+	}
+	return "", fmt.Errorf("adb not found; install the Android SDK platform-tools or set adb_path")
+}
+
+// newADB returns the adb runner used by commands, talking to the device with the given serial
+// (or adb's default device when empty). A variable so tests can substitute a fake.
+var newADB = func(serial string) (ADB, error) {
+	p, err := findADB()
+	if err != nil {
+		return nil, err
+	}
+	return execADB{path: p, serial: serial}, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"crypto/md5"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// fakeADB is a device with files keyed by remote path. Other commands answer from responses, keyed by the
+// joined arguments. Calls are recorded in calls.
+type fakeADB struct {
+	files     map[string]string
+	responses map[string]string
+	calls     []string
+}
+
+func (f *fakeADB) Run(args ...string) ([]byte, error) {
+	joined := strings.Join(args, " ")
+	f.calls = append(f.calls, joined)
+	if out, ok := f.responses[joined]; ok {
+		return []byte(out), nil
+	}
+	switch {
+	case len(args) >= 3 && args[0] == "shell" && args[1] == "ls":
+		var names []string
+		for p := range f.files {
+			if filepath.ToSlash(filepath.Dir(p)) == args[len(args)-1] {
+				names = append(names, filepath.Base(p))
+			}
+		}
+		return []byte(strings.Join(names, "\n") + "\n"), nil
+	case len(args) > 2 && args[0] == "shell" && args[1] == "find":
+		root := strings.Trim(args[2], "'")
+		var lines []string
+		for p, content := range f.files {
+			if p == root || strings.HasPrefix(p, root+"/") {
+				lines = append(lines, fmt.Sprintf("%d:%s", len(content), p))
+			}
+		}
+		if len(lines) == 0 {
+			return []byte("find: " + root + ": No such file or directory\n"), nil
+		}
+		return []byte(strings.Join(lines, "\n") + "\n"), nil
+	case len(args) > 2 && args[0] == "shell" && args[1] == "md5sum":
+		var b strings.Builder
+		for _, a := range args[2:] {
+			p := strings.Trim(a, "'")
+			if content, ok := f.files[p]; ok {
+				fmt.Fprintf(&b, "%x  %s\n", md5.Sum([]byte(content)), p)
+			}
+		}
+		return []byte(b.String()), nil
+	case len(args) == 3 && args[0] == "pull":
+		content, ok := f.files[args[1]]
+		if !ok {
+			return nil, fmt.Errorf("adb pull: remote object '%s' does not exist", args[1])
+		}
+		return nil, os.WriteFile(args[2], []byte(content), 0644)
+	case len(args) == 3 && args[0] == "push":
+		b, err := os.ReadFile(args[1])
+		if err != nil {
+			return nil, err
+		}
+		f.files[args[2]] = string(b)
+		return nil, nil
+	}
+	return nil, fmt.Errorf("fakeADB: unexpected command %q", strings.Join(args, " "))
+}
+
+func TestFindADB_ConfigPath(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "adb")
+	os.WriteFile(p, []byte("#!/bin/sh"), 0755)
+	viper.Set("adb_path", p)
+	defer viper.Set("adb_path", "")
+
+	got, err := findADB()
+	if err != nil || got != p {
+		t.Errorf("findADB = %q, %v; want %q", got, err, p)
+	}
+
+	viper.Set("adb_path", filepath.Join(t.TempDir(), "missing"))
+	if _, err := findADB(); err == nil {
+		t.Error("expected error for missing adb_path")
+	}
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// teamCodeRel is the teamcode package inside a project, with forward slashes for CI scripts.
+const teamCodeRel = "TeamCode/src/main/java/org/firstinspires/ftc/teamcode"
+
+// ciGradleDir holds copies of the project's Gradle files in a TeamCode-only repository, so CI builds
+// with the same libraries and plugins as the laptop instead of the stock SDK files.
+const ciGradleDir = ".ftc-helper-ci"
+
+// ciGradleFiles maps the copies in ciGradleDir to their place in the project.
+var ciGradleFiles = []struct{ Name, ProjectPath string }{
+	{"build.gradle", "build.gradle"},
+	{"build.dependencies.gradle", "build.dependencies.gradle"},
+	{"TeamCode.build.gradle", "TeamCode/build.gradle"},
+}
+
+// CILayout describes the team repository a workflow is generated for.
+type CILayout struct {
+	Root         string // repository root on disk
+	TeamCodeOnly bool   // the repository is the teamcode package, as created by init
+	SDKTag       string // FtcRobotController release to build against (TeamCode-only repositories)
+	GradleFiles  []string
+	Lint         bool
+	TeamCode     string
+	HelperURL    string
+}
+
+// ciLayout works out whether the project's git repository is the whole project or only the
+// teamcode package.
+func ciLayout(projectPath, teamCode string) (CILayout, error) {
+	l := CILayout{TeamCode: teamCodeRel, Lint: true,
+		HelperURL: "https://github.com/" + selfUpdateRepo + "/releases/latest/download/" + helperAssetName("linux", "amd64")}
+	if _, err := os.Stat(filepath.Join(projectPath, ".git")); err == nil {
+		l.Root = projectPath
+		return l, nil
+	}
+	if _, err := os.Stat(filepath.Join(teamCode, ".git")); err != nil {
+		return l, fmt.Errorf("neither %s nor its teamcode package is a git repository", projectPath)
+	}
+	l.Root, l.TeamCodeOnly = teamCode, true
+
+	tag, _, err := detectSDKVersion(projectPath)
+	if err != nil {
+		return l, err
+	}
+	l.SDKTag = tag
+	for _, f := range ciGradleFiles {
+		if _, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(f.ProjectPath))); err == nil {
+			l.GradleFiles = append(l.GradleFiles, f.Name)
+		}
+	}
+	return l, nil
+}
+
+// ciGradleTarget returns where a copied Gradle file goes in the SDK checkout.
+func ciGradleTarget(name string) string {
+	for _, f := range ciGradleFiles {
+		if f.Name == name {
+			return f.ProjectPath
+		}
+	}
+	return name
+}
+
+var ciFuncs = template.FuncMap{"target": ciGradleTarget}
+
+// The templates use [[ ]] so GitHub's ${{ }} expressions pass through untouched.
+var githubWorkflowTemplate = template.Must(template.New("github").Delims("[[", "]]").Funcs(ciFuncs).Parse(`# Generated by 'ftc-helper ci init'. Builds TeamCode and keeps the APK.
+name: FTC build
+
+on:
+  push:
+  pull_request:
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+[[- if .TeamCodeOnly]]
+      # This repository only holds the teamcode package, so build it inside the SDK it was written for.
+      - name: Check out FtcRobotController [[.SDKTag]]
+        uses: actions/checkout@v4
+        with:
+          repository: FIRST-Tech-Challenge/FtcRobotController
+          ref: [[.SDKTag]]
+
+      - name: Check out TeamCode
+        uses: actions/checkout@v4
+        with:
+          path: [[.TeamCode]]
+[[- if .GradleFiles]]
+
+      - name: Use the project's Gradle files
+        run: |
+[[- range .GradleFiles]]
+          cp [[$.TeamCode]]/[[$.GradleDir]]/[[.]] [[target .]]
+[[- end]]
+[[- end]]
+[[- else]]
+      - uses: actions/checkout@v4
+[[- end]]
+
+      - uses: actions/setup-java@v4
+        with:
+          distribution: temurin
+          java-version: '17'
+
+      - uses: android-actions/setup-android@v3
+
+      - uses: gradle/actions/setup-gradle@v4
+
+      - name: Build
+        run: |
+          chmod +x gradlew
+          ./gradlew :TeamCode:assembleDebug
+[[- if .TeamCodeOnly]]
+
+      # Unit tests live in TeamCode/src/test, outside this repository, so CI can't run them.
+[[- else]]
+
+      - name: Unit tests
+        if: hashFiles('TeamCode/src/test/**') != ''
+        run: ./gradlew :TeamCode:testDebugUnitTest
+[[- end]]
+
+      - uses: actions/upload-artifact@v4
+        with:
+          name: TeamCode-debug-${{ github.sha }}
+          path: TeamCode/build/outputs/apk/debug/*.apk
+[[- if .Lint]]
+
+      # Lint runs last: errors fail the job, but the APK above is already kept.
+      - name: Lint
+        run: |
+          mkdir -p "$HOME/bin"
+          curl -fsSL -o "$HOME/bin/ftc-helper" [[.HelperURL]]
+          chmod +x "$HOME/bin/ftc-helper"
+          "$HOME/bin/ftc-helper" --work-dir "$(dirname "$GITHUB_WORKSPACE")" lint "$(basename "$GITHUB_WORKSPACE")"
+[[- end]]
+`))
+
+var gitlabTemplate = template.Must(template.New("gitlab").Delims("[[", "]]").Funcs(ciFuncs).Parse(`# Generated by 'ftc-helper ci init'. Builds TeamCode and keeps the APK.
+build:
+  image: cimg/android:2024.10
+  script:
+[[- if .TeamCodeOnly]]
+    # This repository only holds the teamcode package, so build it inside the SDK it was written for.
+    - git clone --depth 1 --branch [[.SDKTag]] https://github.com/FIRST-Tech-Challenge/FtcRobotController.git /tmp/sdk
+    - rm -rf /tmp/sdk/[[.TeamCode]] && mkdir -p /tmp/sdk/[[.TeamCode]]
+    - cp -r . /tmp/sdk/[[.TeamCode]]/
+[[- range .GradleFiles]]
+    - cp [[$.GradleDir]]/[[.]] /tmp/sdk/[[target .]]
+[[- end]]
+    - cd /tmp/sdk
+[[- end]]
+    - chmod +x gradlew
+    - ./gradlew :TeamCode:assembleDebug
+[[- if .TeamCodeOnly]]
+    # Unit tests live in TeamCode/src/test, outside this repository, so CI can't run them.
+    - mkdir -p "$CI_PROJECT_DIR/apk" && cp TeamCode/build/outputs/apk/debug/*.apk "$CI_PROJECT_DIR/apk/"
+[[- else]]
+    - if [ -d TeamCode/src/test ]; then ./gradlew :TeamCode:testDebugUnitTest; fi
+[[- end]]
+[[- if .Lint]]
+    # Lint runs last: errors fail the job, but the APK is still kept (artifacts when: always).
+    - curl -fsSL -o /tmp/ftc-helper [[.HelperURL]] && chmod +x /tmp/ftc-helper
+    - /tmp/ftc-helper --work-dir "$(dirname "$PWD")" lint "$(basename "$PWD")"
+[[- end]]
+  artifacts:
+    when: always
+    paths:
+[[- if .TeamCodeOnly]]
+      - apk/
+[[- else]]
+      - TeamCode/build/outputs/apk/debug/*.apk
+[[- end]]
+`))
+
+// renderCI renders a workflow template for the layout.
+func renderCI(t *template.Template, l CILayout) (string, error) {
+	data := struct {
+		CILayout
+		GradleDir string
+	}{l, ciGradleDir}
+	var buf bytes.Buffer
+	err := t.Execute(&buf, data)
+	return buf.String(), err
+}
+
+// copyCIGradleFiles refreshes the copies of the project's Gradle files in a TeamCode-only repository.
+func copyCIGradleFiles(projectPath string, l CILayout) error {
+	if len(l.GradleFiles) == 0 {
+		return nil
+	}
+	dir := filepath.Join(l.Root, ciGradleDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, name := range l.GradleFiles {
+		b, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(ciGradleTarget(name))))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ci: generate CI configuration for the team repository
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Set up continuous integration for a team repository",
+}
+
+var ciInitCmd = &cobra.Command{
+	Use:   "init [project_name]",
+	Short: "Write a GitHub Actions (or GitLab CI) workflow that builds, lints and tests TeamCode",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		gitlab, _ := cmd.Flags().GetBool("gitlab")
+		github, _ := cmd.Flags().GetBool("github")
+		noLint, _ := cmd.Flags().GetBool("no-lint")
+		force, _ := cmd.Flags().GetBool("force")
+		if !cmd.Flags().Changed("github") && gitlab {
+			github = false
+		}
+
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		l, err := ciLayout(projectPath, teamCodeDir(args[0]))
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		l.Lint = !noLint
+
+		type ciFile struct {
+			path string
+			tmpl *template.Template
+		}
+		var outputs []ciFile
+		if github {
+			outputs = append(outputs, ciFile{filepath.Join(l.Root, ".github", "workflows", "ftc-build.yml"), githubWorkflowTemplate})
+		}
+		if gitlab {
+			outputs = append(outputs, ciFile{filepath.Join(l.Root, ".gitlab-ci.yml"), gitlabTemplate})
+		}
+		if len(outputs) == 0 {
+			fmt.Println("Nothing to write; use --github or --gitlab.")
+			return
+		}
+
+		for _, o := range outputs {
+			if _, err := os.Stat(o.path); err == nil && !force {
+				fmt.Printf("%s already exists; use --force to replace it.\n", o.path)
+				return
+			}
+		}
+		for _, o := range outputs {
+			content, err := renderCI(o.tmpl, l)
+			if err != nil {
+				fmt.Println("Error generating workflow:", err)
+				return
+			}
+			if err := os.MkdirAll(filepath.Dir(o.path), 0755); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if err := os.WriteFile(o.path, []byte(content), 0644); err != nil {
+				fmt.Println("Error writing workflow:", err)
+				return
+			}
+			fmt.Println("Wrote", o.path)
+		}
+
+		if l.TeamCodeOnly {
+			if err := copyCIGradleFiles(projectPath, l); err != nil {
+				fmt.Println("Error copying Gradle files:", err)
+				return
+			}
+			fmt.Printf("The repository holds only TeamCode; CI builds it inside FtcRobotController %s.\n", l.SDKTag)
+			if len(l.GradleFiles) > 0 {
+				fmt.Printf("Copied the project's Gradle files to %s. Run 'ftc-helper ci init %s --force' again after 'lib add'.\n", ciGradleDir, args[0])
+			}
+			fmt.Println("Unit tests in TeamCode/src/test are outside the repository, so CI doesn't run them.")
+			fmt.Printf("Commit and push with 'ftc-helper push %s \"Add CI\"'.\n", args[0])
+		} else {
+			fmt.Println("Commit and push the new files with git; 'push' only commits the teamcode package.")
+		}
+	},
+}
+
+func init() {
+	ciInitCmd.Flags().Bool("github", true, "Write a GitHub Actions workflow")
+	ciInitCmd.Flags().Bool("gitlab", false, "Write a GitLab CI pipeline (instead of GitHub Actions unless --github is also given)")
+	ciInitCmd.Flags().Bool("no-lint", false, "Leave out the 'ftc-helper lint' step")
+	ciInitCmd.Flags().Bool("force", false, "Replace existing CI files")
+	ciCmd.AddCommand(ciInitCmd)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCILayout(t *testing.T) {
+	tests := []struct {
+		name         string
+		gitDir       string // relative to the project, "" for none
+		wantErr      bool
+		teamCodeOnly bool
+	}{
+		{"full project", ".git", false, false},
+		{"teamcode only", filepath.Join(filepath.FromSlash(teamCodeRel), ".git"), false, true},
+		{"no repository", "", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := makeTestProject(t, t.TempDir(), "robot")
+			if err := os.WriteFile(filepath.Join(root, "build.dependencies.gradle"),
+				[]byte("implementation 'org.firstinspires.ftc:RobotCore:10.1.1'\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.gitDir != "" {
+				if err := os.MkdirAll(filepath.Join(root, tt.gitDir), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			teamCode := filepath.Join(root, filepath.FromSlash(teamCodeRel))
+			l, err := ciLayout(root, teamCode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if l.TeamCodeOnly != tt.teamCodeOnly {
+				t.Errorf("TeamCodeOnly = %v, want %v", l.TeamCodeOnly, tt.teamCodeOnly)
+			}
+			if tt.teamCodeOnly {
+				if l.Root != teamCode || l.SDKTag != "v10.1.1" {
+					t.Errorf("Root = %s, SDKTag = %s", l.Root, l.SDKTag)
+				}
+				if strings.Join(l.GradleFiles, ",") != "build.gradle,build.dependencies.gradle,TeamCode.build.gradle" {
+					t.Errorf("GradleFiles = %v", l.GradleFiles)
+				}
+			} else if l.Root != root {
+				t.Errorf("Root = %s, want %s", l.Root, root)
+			}
+		})
+	}
+}
+
+func TestRenderCI(t *testing.T) {
+	full := CILayout{TeamCode: teamCodeRel, Lint: true, HelperURL: "https://example.com/ftc-helper"}
+	teamOnly := full
+	teamOnly.TeamCodeOnly, teamOnly.SDKTag, teamOnly.GradleFiles = true, "v10.1.1", []string{"TeamCode.build.gradle"}
+	noLint := full
+	noLint.Lint = false
+
+	tests := []struct {
+		name    string
+		layout  CILayout
+		gitlab  bool
+		want    []string
+		notWant []string
+	}{
+		{"github full", full, false,
+			[]string{"./gradlew :TeamCode:assembleDebug", "${{", "hashFiles('TeamCode/src/test/**')", "actions/upload-artifact@v4", "lint \"$(basename"},
+			[]string{"FtcRobotController\n", "ref:"}},
+		{"github teamcode only", teamOnly, false,
+			[]string{"ref: v10.1.1", "path: " + teamCodeRel, "cp " + teamCodeRel + "/.ftc-helper-ci/TeamCode.build.gradle TeamCode/build.gradle"},
+			[]string{"testDebugUnitTest"}},
+		{"github without lint", noLint, false, []string{"assembleDebug"}, []string{"ftc-helper\" --work-dir"}},
+		{"gitlab full", full, true,
+			[]string{"./gradlew :TeamCode:assembleDebug", "- TeamCode/build/outputs/apk/debug/*.apk", "when: always", "testDebugUnitTest"},
+			[]string{"git clone"}},
+		{"gitlab teamcode only", teamOnly, true,
+			[]string{"--branch v10.1.1", "cp .ftc-helper-ci/TeamCode.build.gradle /tmp/sdk/TeamCode/build.gradle", "- apk/"},
+			[]string{"testDebugUnitTest"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := githubWorkflowTemplate
+			if tt.gitlab {
+				tmpl = gitlabTemplate
+			}
+			out, err := renderCI(tmpl, tt.layout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("missing %q in:\n%s", w, out)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(out, w) {
+					t.Errorf("unexpected %q in:\n%s", w, out)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderCI_LintAfterArtifact(t *testing.T) {
+	l := CILayout{TeamCode: teamCodeRel, Lint: true, HelperURL: "https://example.com/ftc-helper"}
+	out, err := renderCI(githubWorkflowTemplate, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Lint errors fail the job, so the APK must already be uploaded when lint runs.
+	if upload, lint := strings.Index(out, "actions/upload-artifact"), strings.Index(out, "name: Lint"); lint < upload {
+		t.Errorf("lint runs before the APK is uploaded:\n%s", out)
+	}
+}
+
+func TestCopyCIGradleFiles(t *testing.T) {
+	root := makeTestProject(t, t.TempDir(), "robot")
+	teamCode := filepath.Join(root, filepath.FromSlash(teamCodeRel))
+	l := CILayout{Root: teamCode, TeamCodeOnly: true, GradleFiles: []string{"build.gradle"}}
+	if err := copyCIGradleFiles(root, l); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(teamCode, ciGradleDir, "build.gradle"))
+	if err != nil || string(b) != "// root" {
+		t.Errorf("copied build.gradle = %q, %v", b, err)
+	}
+}
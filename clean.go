@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// findBuildOutputDirs returns the build output directories inside a project. Only the locations Gradle
+// and Android Studio write to are checked; sources and .git are never searched.
+func findBuildOutputDirs(projectPath string) ([]string, error) {
+	outputs, err := buildOutputs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	dirs := make([]string, 0, len(outputs))
+	for rel := range outputs {
+		dirs = append(dirs, filepath.Join(projectPath, filepath.FromSlash(rel)))
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// dirSize returns the total size of the regular files under p.
+func dirSize(p string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(p, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// humanSize formats a byte count like "12.3 MB".
+func humanSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// ProjectUsage is the disk usage of a project.
+type ProjectUsage struct {
+	Total int64
+	Build int64
+	Err   error
+}
+
+// projectUsage measures each project directory concurrently, bounded by the number of CPUs.
+func projectUsage(paths []string) map[string]ProjectUsage {
+	results := make(map[string]ProjectUsage, len(paths))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.NumCPU())
+
+	for _, p := range paths {
+		wg.Add(1)
+		go func(p string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var u ProjectUsage
+			u.Total, u.Err = dirSize(p)
+			if u.Err == nil {
+				dirs, err := findBuildOutputDirs(p)
+				u.Err = err
+				for _, d := range dirs {
+					n, err := dirSize(d)
+					if err != nil {
+						u.Err = err
+						break
+					}
+					u.Build += n
+				}
+			}
+
+			mu.Lock()
+			results[p] = u
+			mu.Unlock()
+		}(p)
+	}
+	wg.Wait()
+	return results
+}
+
+// cleanProject removes (or with dryRun only lists) the build outputs of a project and returns the bytes freed.
+func cleanProject(name, projectPath string, dryRun bool) (int64, error) {
+	dirs, err := findBuildOutputDirs(projectPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, d := range dirs {
+		size, _ := dirSize(d)
+		rel, _ := filepath.Rel(projectPath, d)
+		if dryRun {
+			fmt.Printf("  would remove %-40s %s\n", filepath.Join(name, rel), humanSize(size))
+		} else {
+			if err := os.RemoveAll(d); err != nil {
+				return total, err
+			}
+			fmt.Printf("  removed %-40s %s\n", filepath.Join(name, rel), humanSize(size))
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// clean: remove Gradle build outputs and caches
+var cleanCmd = &cobra.Command{
+	Use:   "clean [project_name]",
+	Short: "Remove Gradle build directories and caches from a project",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		var names []string
+		switch {
+		case all && len(args) == 0:
+			projects, err := findProjects(workDir)
+			if err != nil {
+				fmt.Println("Error reading working directory:", err)
+				return
+			}
+			names = projects
+		case !all && len(args) == 1:
+			if _, err := existingProject(args[0]); err != nil {
+				fmt.Println(err)
+				return
+			}
+			names = args
+		default:
+			fmt.Println("Specify a project name or --all.")
+			return
+		}
+
+		var freed int64
+		for _, name := range names {
+			n, err := cleanProject(name, filepath.Join(workDir, name), dryRun)
+			freed += n
+			if err != nil {
+				fmt.Printf("Error cleaning %s: %v\n", name, err)
+			}
+		}
+
+		if dryRun {
+			fmt.Println("Would free", humanSize(freed))
+		} else {
+			fmt.Println("Freed", humanSize(freed))
+		}
+	},
+}
+
+func init() {
+	cleanCmd.Flags().Bool("all", false, "Clean every project in the work directory")
+	cleanCmd.Flags().BoolP("dry-run", "n", false, "Only list what would be removed")
+}
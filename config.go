@@ -0,0 +1,490 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigKey describes a setting that can be stored in the config file.
+type ConfigKey struct {
+	Key         string
+	Type        string // string, path, bool, int or map
+	Entry       string // for maps, the type of each entry
+	Description string
+	// Secret values are masked when displayed.
+	Secret bool
+}
+
+// configSchema lists every known setting, in the order shown by `config keys`.
+var configSchema = []ConfigKey{
+	{Key: "work_dir", Type: "path", Description: "Directory where projects are stored"},
+	{Key: "workspace", Type: "string", Description: "Active workspace (see workspace use; env FTC_HELPER_WORKSPACE)"},
+	{Key: "workspaces", Type: "map", Entry: "path", Description: "Named work directories (see workspace add)"},
+	{Key: "archive_dir", Type: "path", Description: "Where project archive stores zips (default <work dir>/_archive)"},
+	{Key: "adb_path", Type: "path", Description: "adb executable used to talk to the robot (default: Android SDK platform-tools)"},
+	{Key: "robot", Type: "string", Description: "Active robot (see robot use)"},
+	{Key: "robots", Type: "map", Entry: "map", Description: "Saved robots with ssid, ip and port (see robot add)"},
+	{Key: "android_studio_path", Type: "path", Description: "Android Studio executable used by launch"},
+	{Key: "github_token", Type: "string", Description: "GitHub token for API requests (env GITHUB_TOKEN)", Secret: true},
+	{Key: "github_client_id", Type: "string", Description: "OAuth app client ID used by auth login"},
+	{Key: "github_api_url", Type: "string", Description: "GitHub REST API base URL"},
+	{Key: "github_url", Type: "string", Description: "GitHub web base URL used for device login"},
+	{Key: "cache_dir", Type: "path", Description: "Where downloaded SDK releases and tools are cached"},
+	{Key: "update_check", Type: "bool", Description: "Print a notice when SDK or tool updates are available"},
+	{Key: "update_check_interval", Type: "int", Description: "Hours between automatic update checks (default 24)"},
+	{Key: "format_on_push", Type: "bool", Description: "Run fmt on the project before push commits"},
+	{Key: "google_java_format_version", Type: "string", Description: "google-java-format version used by fmt"},
+	{Key: "ktfmt_version", Type: "string", Description: "ktfmt version used by fmt for Kotlin files"},
+	{Key: "libraries_file", Type: "path", Description: "YAML file with extra libraries for lib add"},
+	{Key: "team.number", Type: "string", Description: "FTC team number"},
+	{Key: "team.name", Type: "string", Description: "Team name"},
+	{Key: "team.season", Type: "string", Description: "Season start year, e.g. 2025 (defaults to the current season)"},
+	{Key: "team.git_org", Type: "string", Description: "Default GitHub owner for new repositories"},
+	{Key: "team.sdk_version", Type: "string", Description: "Preferred FtcRobotController release, e.g. v10.1"},
+	{Key: "team.language", Type: "string", Description: "Default TeamCode language: java or kotlin"},
+	{Key: "team.author_name", Type: "string", Description: "Commit author name used by push"},
+	{Key: "team.author_email", Type: "string", Description: "Commit author email used by push"},
+}
+
+// setConfigDefaults registers default values with viper.
+func setConfigDefaults() {
+	home, _ := os.UserHomeDir()
+	viper.SetDefault("work_dir", home+"/StudioProjects")
+	if cache, err := os.UserCacheDir(); err == nil {
+		viper.SetDefault("cache_dir", filepath.Join(cache, "ftc-helper"))
+	}
+}
+
+// lookupConfigKey returns the schema entry for key.
+func lookupConfigKey(key string) (ConfigKey, error) {
+	key = strings.ToLower(key)
+	for _, k := range configSchema {
+		if k.Key == key {
+			return k, nil
+		}
+		// An entry of a section, e.g. workspaces.archive.
+		if name, ok := strings.CutPrefix(key, k.Key+"."); ok && k.Type == "map" && name != "" && !strings.Contains(name, ".") {
+			return ConfigKey{Key: key, Type: k.Entry, Description: k.Description}, nil
+		}
+	}
+	return ConfigKey{}, fmt.Errorf("unknown config key %q. Run 'ftc-helper config keys' to list valid keys", key)
+}
+
+// parseValue converts the string given on the command line to the key's type.
+func (k ConfigKey) parseValue(s string) (interface{}, error) {
+	switch k.Type {
+	case "bool":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects true or false", k.Key)
+		}
+		return b, nil
+	case "int":
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects a whole number", k.Key)
+		}
+		return n, nil
+	case "map":
+		return nil, fmt.Errorf("%s is a section; set its entries individually", k.Key)
+	case "path":
+		if s == "" {
+			return nil, fmt.Errorf("%s expects a path", k.Key)
+		}
+		if strings.HasPrefix(s, "~") {
+			home, _ := os.UserHomeDir()
+			s = filepath.Join(home, s[1:])
+		}
+		return s, nil
+	default:
+		return s, nil
+	}
+}
+
+// display formats a value for output. Secrets are masked completely; only whether one is set shows.
+func (k ConfigKey) display(v interface{}) string {
+	s := fmt.Sprint(v)
+	if k.Secret && s != "" {
+		return strings.Repeat("*", 8)
+	}
+	return s
+}
+
+// envKeyReplacer turns an upper-cased config key into the environment variable that overrides it:
+// dots become underscores, and the workspace keys get an FTC_HELPER_ prefix because CI servers such
+// as Jenkins set WORKSPACE for their own use.
+var envKeyReplacer = strings.NewReplacer(".", "_", "WORKSPACE", "FTC_HELPER_WORKSPACE")
+
+// envName returns the environment variable that overrides the key.
+func (k ConfigKey) envName() string {
+	return envKeyReplacer.Replace(strings.ToUpper(k.Key))
+}
+
+// configFlags maps config keys to the persistent flags bound to them.
+var configFlags = map[string]string{
+	"work_dir":  "work-dir",
+	"workspace": "workspace",
+}
+
+// configOrigin reports where the current value of a key comes from: flag, env, file, default or unset.
+func configOrigin(k ConfigKey) string {
+	if flag, ok := configFlags[k.Key]; ok {
+		if f := rootCmd.PersistentFlags().Lookup(flag); f != nil && f.Changed {
+			return "flag"
+		}
+	}
+	if _, ok := os.LookupEnv(k.envName()); ok {
+		return "env"
+	}
+	if viper.InConfig(k.Key) {
+		return "file"
+	}
+	if viper.IsSet(k.Key) {
+		return "default"
+	}
+	return "unset"
+}
+
+// configFilePath returns the config file in use, or the default location if none was loaded.
+func configFilePath() string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	if p := viper.ConfigFileUsed(); p != "" {
+		return p
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".ftc-helper.yaml")
+}
+
+// readConfigFile reads the YAML config file into a map. A missing file yields an empty map.
+func readConfigFile(p string) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	b, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+	return m, nil
+}
+
+// writeConfigFile writes the map back as YAML, creating the file if needed. The file can hold the
+// GitHub token, so it is only readable by the current user.
+func writeConfigFile(p string, m map[string]interface{}) error {
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(p, b, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file.
+	return os.Chmod(p, 0600)
+}
+
+// setConfigKey sets a dotted key such as "team.number" in a nested map.
+func setConfigKey(m map[string]interface{}, key string, value interface{}) error {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := m[part]
+		if !ok {
+			child := map[string]interface{}{}
+			m[part] = child
+			m = child
+			continue
+		}
+		child, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s is not a section", part)
+		}
+		m = child
+	}
+	m[parts[len(parts)-1]] = value
+	return nil
+}
+
+// unsetConfigKey removes a dotted key and any sections left empty. It reports whether the key existed.
+func unsetConfigKey(m map[string]interface{}, key string) bool {
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) == 1 {
+		_, ok := m[key]
+		delete(m, key)
+		return ok
+	}
+	child, ok := m[parts[0]].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	removed := unsetConfigKey(child, parts[1])
+	if len(child) == 0 {
+		delete(m, parts[0])
+	}
+	return removed
+}
+
+// saveConfigValue writes a single key to the config file and updates the running config.
+func saveConfigValue(key string, value interface{}) (string, error) {
+	if key == "" || strings.HasPrefix(key, ".") || strings.HasSuffix(key, ".") {
+		return "", errors.New("invalid key")
+	}
+	p := configFilePath()
+	m, err := readConfigFile(p)
+	if err != nil {
+		return "", err
+	}
+	if err := setConfigKey(m, strings.ToLower(key), value); err != nil {
+		return "", err
+	}
+	if err := writeConfigFile(p, m); err != nil {
+		return "", err
+	}
+	viper.Set(key, value)
+	return p, nil
+}
+
+// ensureConfigFile creates the config file with the default work_dir if it does not exist.
+// It reports whether the file was created.
+func ensureConfigFile() (string, bool, error) {
+	p := configFilePath()
+	if _, err := os.Stat(p); err == nil {
+		return p, false, nil
+	}
+	m := map[string]interface{}{"work_dir": viper.GetString("work_dir")}
+	if err := writeConfigFile(p, m); err != nil {
+		return "", false, err
+	}
+	return p, true, nil
+}
+
+// editorCommand returns the editor to use for `config edit`. Blank VISUAL or EDITOR values are ignored.
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if e := strings.TrimSpace(os.Getenv(env)); e != "" {
+			return e
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// maskSecrets replaces the secret values in settings, as returned by viper.AllSettings, with their
+// masked display form.
+func maskSecrets(settings map[string]interface{}) {
+	for _, k := range configSchema {
+		if !k.Secret || !viper.IsSet(k.Key) {
+			continue
+		}
+		if v := k.display(viper.Get(k.Key)); v != "" {
+			setConfigKey(settings, k.Key, v)
+		}
+	}
+}
+
+// config: print current configuration as YAML
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Print the current configuration as YAML",
+	Run: func(cmd *cobra.Command, args []string) {
+		showOrigin, _ := cmd.Flags().GetBool("show-origin")
+		if showOrigin {
+			for _, k := range configSchema {
+				origin := configOrigin(k)
+				if origin == "unset" {
+					continue
+				}
+				fmt.Printf("%-8s %-20s %s\n", origin, k.Key, k.display(viper.Get(k.Key)))
+			}
+			return
+		}
+
+		settings := viper.AllSettings()
+		maskSecrets(settings)
+		// Marshal to JSON first, then convert to YAML for pretty output
+		b, err := json.Marshal(settings)
+		if err != nil {
+			fmt.Println("Error marshaling config to JSON:", err)
+			return
+		}
+		y, err := yaml.JSONToYAML(b)
+		if err != nil {
+			fmt.Println("Error converting config to YAML:", err)
+			return
+		}
+		fmt.Println(string(y))
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print a configuration value",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		k, err := lookupConfigKey(args[0])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		if !viper.IsSet(k.Key) {
+			fmt.Println("(not set)")
+			return
+		}
+		fmt.Println(k.display(viper.Get(k.Key)))
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Set a configuration value, e.g. team.number 12345",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		k, err := lookupConfigKey(args[0])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		v, err := k.parseValue(args[1])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		p, err := saveConfigValue(k.Key, v)
+		if err != nil {
+			fmt.Println("Error saving config:", err)
+			return
+		}
+		fmt.Printf("Set %s = %s in %s\n", k.Key, k.display(v), p)
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset [key]",
+	Short: "Remove a value from the config file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		k, err := lookupConfigKey(args[0])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		p := configFilePath()
+		m, err := readConfigFile(p)
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		if !unsetConfigKey(m, k.Key) {
+			fmt.Printf("%s is not set in %s\n", k.Key, p)
+			return
+		}
+		if err := writeConfigFile(p, m); err != nil {
+			fmt.Println("Error saving config:", err)
+			return
+		}
+		fmt.Printf("Removed %s from %s\n", k.Key, p)
+	},
+}
+
+var configKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "List the known configuration keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		keys := append([]ConfigKey{}, configSchema...)
+		sort.SliceStable(keys, func(i, j int) bool { return keys[i].Key < keys[j].Key })
+		for _, k := range keys {
+			fmt.Printf("%-20s %-7s %s\n", k.Key, k.Type, k.Description)
+		}
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create the config file with default values",
+	Run: func(cmd *cobra.Command, args []string) {
+		p, created, err := ensureConfigFile()
+		if err != nil {
+			fmt.Println("Error creating config file:", err)
+			return
+		}
+		if !created {
+			fmt.Println("Config file already exists:", p)
+			return
+		}
+		fmt.Println("Created config file:", p)
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file",
+	Run: func(cmd *cobra.Command, args []string) {
+		p := configFilePath()
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			fmt.Println(p, "(does not exist yet)")
+			return
+		}
+		fmt.Println(p)
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $EDITOR",
+	Run: func(cmd *cobra.Command, args []string) {
+		p, _, err := ensureConfigFile()
+		if err != nil {
+			fmt.Println("Error creating config file:", err)
+			return
+		}
+
+		// The editor may include arguments, e.g. "code --wait".
+		editor := strings.Fields(editorCommand())
+		editCmd := exec.Command(editor[0], append(editor[1:], p)...)
+		editCmd.Stdin = os.Stdin
+		editCmd.Stdout = os.Stdout
+		editCmd.Stderr = os.Stderr
+		if err := editCmd.Run(); err != nil {
+			fmt.Println("Error running editor:", err)
+			return
+		}
+
+		if _, err := readConfigFile(p); err != nil {
+			fmt.Println("Warning: config file is not valid YAML:", err)
+		}
+	},
+}
+
+func init() {
+	configCmd.Flags().Bool("show-origin", false, "Show whether each value comes from a flag, env var, the config file or a default")
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configKeysCmd)
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configEditCmd)
+}
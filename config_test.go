@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestSaveConfigValue(t *testing.T) {
+	cfgFile = filepath.Join(t.TempDir(), "config.yaml")
+	defer func() {
+		cfgFile = ""
+		viper.Set("team.number", "")
+		viper.Set("team.name", "")
+	}()
+	os.WriteFile(cfgFile, []byte("work_dir: /projects\n"), 0644)
+
+	if _, err := saveConfigValue("team.number", "12345"); err != nil {
+		t.Fatalf("saveConfigValue: %v", err)
+	}
+	if _, err := saveConfigValue("team.name", "Gear Grinders"); err != nil {
+		t.Fatalf("saveConfigValue: %v", err)
+	}
+
+	b, _ := os.ReadFile(cfgFile)
+	want := "team:\n  name: Gear Grinders\n  number: \"12345\"\nwork_dir: /projects\n"
+	if string(b) != want {
+		t.Fatalf("config file =\n%s\nwant\n%s", b, want)
+	}
+	if p := teamProfile(); p.Number != "12345" || p.Name != "Gear Grinders" {
+		t.Fatalf("teamProfile not updated: %+v", p)
+	}
+
+	if _, err := saveConfigValue("work_dir.sub", "x"); err == nil || !strings.Contains(err.Error(), "not a section") {
+		t.Fatalf("expected section error, got %v", err)
+	}
+}
+
+func TestUnsetConfigKey(t *testing.T) {
+	m := map[string]interface{}{
+		"work_dir": "/projects",
+		"team":     map[string]interface{}{"number": "12345"},
+	}
+	if !unsetConfigKey(m, "team.number") {
+		t.Fatal("expected team.number to be removed")
+	}
+	if _, ok := m["team"]; ok {
+		t.Fatalf("empty team section should be removed: %v", m)
+	}
+	if unsetConfigKey(m, "team.name") {
+		t.Fatal("unset of missing key should report false")
+	}
+	if m["work_dir"] != "/projects" {
+		t.Fatalf("unrelated key changed: %v", m)
+	}
+}
+
+func TestConfigKeyParseValue(t *testing.T) {
+	if _, err := lookupConfigKey("team.colour"); err == nil {
+		t.Fatal("expected unknown key error")
+	}
+
+	b := ConfigKey{Key: "x", Type: "bool"}
+	if v, err := b.parseValue("true"); err != nil || v != true {
+		t.Fatalf("bool parse = %v, %v", v, err)
+	}
+	if _, err := b.parseValue("maybe"); err == nil {
+		t.Fatal("expected bool parse error")
+	}
+	n := ConfigKey{Key: "x", Type: "int"}
+	if v, err := n.parseValue("42"); err != nil || v != 42 {
+		t.Fatalf("int parse = %v, %v", v, err)
+	}
+
+	secret := ConfigKey{Key: "github_token", Secret: true}
+	for _, v := range []string{"ghp_abcdef1234", "abcd", "x"} {
+		if got := secret.display(v); got != "********" {
+			t.Fatalf("secret display(%q) = %q", v, got)
+		}
+	}
+	if got := secret.display(""); got != "" {
+		t.Fatalf("unset secret display = %q", got)
+	}
+}
+
+func TestMaskSecrets(t *testing.T) {
+	viper.Set("github_token", "ghp_SECRET123")
+	defer viper.Set("github_token", "")
+
+	settings := viper.AllSettings()
+	maskSecrets(settings)
+	if got := settings["github_token"]; got != "********" {
+		t.Errorf("github_token = %v, want it masked", got)
+	}
+}
+
+func TestWriteConfigFile_Private(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(p, []byte("work_dir: /projects\n"), 0644)
+	if err := writeConfigFile(p, map[string]interface{}{"github_token": "ghp_x"}); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && fi.Mode().Perm() != 0600 {
+		t.Errorf("config file mode = %v, want 0600", fi.Mode().Perm())
+	}
+}
+
+func TestEditorCommand_Blank(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "  ")
+	if got := strings.Fields(editorCommand()); len(got) == 0 {
+		t.Error("editorCommand returned no editor for a blank EDITOR")
+	}
+	t.Setenv("EDITOR", "code --wait")
+	if got := editorCommand(); got != "code --wait" {
+		t.Errorf("editorCommand = %q", got)
+	}
+}
+
+func TestLookupConfigKey_SectionEntry(t *testing.T) {
+	k, err := lookupConfigKey("workspaces.Archive")
+	if err != nil || k.Key != "workspaces.archive" || k.Type != "path" {
+		t.Errorf("lookupConfigKey(workspaces.Archive) = %+v, %v", k, err)
+	}
+	for _, key := range []string{"workspaces.", "workspaces.a.b", "team.number.x", "work_dir.x"} {
+		if _, err := lookupConfigKey(key); err == nil {
+			t.Errorf("lookupConfigKey(%q) should fail", key)
+		}
+	}
+}
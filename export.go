@@ -0,0 +1,496 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// exportRootFiles are the Gradle files outside TeamCode needed to build an exported project.
+var exportRootFiles = []string{
+	"build.gradle",
+	"build.common.gradle",
+	"build.dependencies.gradle",
+	"settings.gradle",
+	"gradle.properties",
+	"gradlew",
+	"gradlew.bat",
+	"gradle/wrapper/gradle-wrapper.jar",
+	"gradle/wrapper/gradle-wrapper.properties",
+	manifestFile,
+}
+
+// isSecretFile reports whether a file name looks like credentials or signing keys that must not be shared.
+func isSecretFile(name string) bool {
+	lower := strings.ToLower(name)
+	switch lower {
+	case "local.properties", "keystore.properties", "secrets.properties", "google-services.json":
+		return true
+	}
+	if strings.HasPrefix(lower, ".env") {
+		return true
+	}
+	for _, ext := range []string{".jks", ".keystore", ".p12", ".pem", ".key"} {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// exportFiles returns the slash-separated paths, relative to the project root, that go into an export:
+// the Gradle files at the root and everything under TeamCode except build outputs, git data and secrets.
+func exportFiles(projectPath string) ([]string, error) {
+	var files []string
+	for _, f := range exportRootFiles {
+		if info, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(f))); err == nil && info.Mode().IsRegular() {
+			files = append(files, f)
+		}
+	}
+
+	outputs, err := buildOutputs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	teamCode := filepath.Join(projectPath, "TeamCode")
+	err = filepath.WalkDir(teamCode, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(projectPath, p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if outputs[filepath.ToSlash(rel)] || d.Name() == ".git" || d.Name() == ".idea" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || isSecretFile(d.Name()) {
+			return nil
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ExportInfo identifies exactly which code an export contains.
+type ExportInfo struct {
+	Project    string
+	SDKVersion string
+	Commit     string
+	Exported   time.Time
+}
+
+func (i ExportInfo) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Project:     %s\n", i.Project)
+	fmt.Fprintf(&b, "SDK version: %s\n", valueOr(i.SDKVersion, "unknown"))
+	fmt.Fprintf(&b, "Git commit:  %s\n", valueOr(i.Commit, "not a git repository"))
+	fmt.Fprintf(&b, "Exported:    %s\n", i.Exported.Format(time.RFC1123))
+	return b.String()
+}
+
+func valueOr(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// gitCommit returns the HEAD commit of the repository in dir, with "-dirty" appended when there are
+// uncommitted changes, or "" if dir is not a git repository.
+func gitCommit(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	commit := strings.TrimSpace(string(out))
+	if status, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output(); err == nil && len(bytes.TrimSpace(status)) > 0 {
+		commit += "-dirty"
+	}
+	return commit
+}
+
+// exportInfo collects the SDK version from the manifest and the TeamCode commit.
+func exportInfo(name, projectPath, teamCodePath string) ExportInfo {
+	info := ExportInfo{Project: name, Exported: time.Now()}
+	if m, err := readManifest(projectPath); err == nil {
+		info.SDKVersion = m.SDKVersion
+	}
+	info.Commit = gitCommit(teamCodePath)
+	return info
+}
+
+// bundleWriter adds files to an archive.
+type bundleWriter interface {
+	add(name string, mode os.FileMode, modTime time.Time, r io.Reader) error
+	Close() error
+}
+
+type zipBundle struct{ zw *zip.Writer }
+
+func (z *zipBundle) add(name string, mode os.FileMode, modTime time.Time, r io.Reader) error {
+	hdr := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modTime}
+	hdr.SetMode(mode)
+	w, err := z.zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func (z *zipBundle) Close() error { return z.zw.Close() }
+
+type tarGzBundle struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (t *tarGzBundle) add(name string, mode os.FileMode, modTime time.Time, r io.Reader) error {
+	// tar needs the size up front.
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{Name: name, Mode: int64(mode.Perm()), Size: int64(len(b)), ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := t.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = t.tw.Write(b)
+	return err
+}
+
+func (t *tarGzBundle) Close() error {
+	if err := t.tw.Close(); err != nil {
+		return err
+	}
+	return t.gz.Close()
+}
+
+func newBundleWriter(format string, w io.Writer) (bundleWriter, error) {
+	switch format {
+	case "zip":
+		return &zipBundle{zip.NewWriter(w)}, nil
+	case "tar.gz", "tgz":
+		gz := gzip.NewWriter(w)
+		return &tarGzBundle{gz: gz, tw: tar.NewWriter(gz)}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q (use zip or tar.gz)", format)
+}
+
+// writeExport writes files from projectPath plus an EXPORT.txt with info into dst, all under a
+// top-level directory named after the project.
+func writeExport(dst, format, projectPath string, files []string, info ExportInfo) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	bw, err := newBundleWriter(format, f)
+	if err != nil {
+		f.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	err = bw.add(path.Join(info.Project, "EXPORT.txt"), 0644, info.Exported, strings.NewReader(info.String()))
+	for _, rel := range files {
+		if err != nil {
+			break
+		}
+		err = func() error {
+			p := filepath.Join(projectPath, filepath.FromSlash(rel))
+			st, err := os.Stat(p)
+			if err != nil {
+				return err
+			}
+			in, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer in.Close()
+			return bw.add(path.Join(info.Project, rel), st.Mode(), st.ModTime(), in)
+		}()
+	}
+	if err == nil {
+		err = bw.Close()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst)
+	}
+	return err
+}
+
+// opModeAnnotation matches @TeleOp / @Autonomous annotations and an optional name.
+var opModeAnnotation = regexp.MustCompile(`@(TeleOp|Autonomous)\s*(?:\(([^)]*)\))?`)
+var annotationName = regexp.MustCompile(`name\s*=\s*"([^"]*)"`)
+
+// OpModeEntry is an OpMode found in a source file, for the listing's table of contents.
+type OpModeEntry struct {
+	Name     string
+	Kind     string
+	File     string
+	Disabled bool
+}
+
+// opModeClass matches a Java or Kotlin class declaration and captures the class name.
+var opModeClass = regexp.MustCompile(`\b(?:class|object)\s+(\w+)`)
+
+var disabledAnnotation = regexp.MustCompile(`@Disabled\b`)
+
+// annotationBlock returns the annotations and modifiers that the annotation at position at belongs
+// to, from the end of the previous statement or brace up to the class declaration, and the name of
+// that class ("" if no class follows). code must have comments and strings blanked by stripJava.
+func annotationBlock(code string, at int) (block, class string) {
+	start := strings.LastIndexAny(code[:at], ";{}") + 1
+	loc := opModeClass.FindStringSubmatchIndex(code[at:])
+	if loc == nil {
+		return code[start:], ""
+	}
+	return code[start : at+loc[0]], code[at+loc[2] : at+loc[3]]
+}
+
+// findOpModes scans a source file for OpMode annotations. OpModes without a name use the class name,
+// the same as the Driver Station. @Disabled only applies to the class it annotates.
+func findOpModes(file, src string) []OpModeEntry {
+	var entries []OpModeEntry
+	base := strings.TrimSuffix(path.Base(file), path.Ext(file))
+	code := stripJava(src)
+	for _, m := range opModeAnnotation.FindAllStringSubmatchIndex(code, -1) {
+		block, class := annotationBlock(code, m[0])
+		e := OpModeEntry{Name: valueOr(class, base), Kind: code[m[2]:m[3]], File: file, Disabled: disabledAnnotation.MatchString(block)}
+		if m[4] >= 0 {
+			// Read the name from the original source; string contents are blanked in code.
+			if n := annotationName.FindStringSubmatch(src[m[4]:m[5]]); n != nil {
+				e.Name = n[1]
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// isSourceFile reports whether a file belongs in the code listing.
+func isSourceFile(rel string) bool {
+	return strings.HasPrefix(rel, "TeamCode/src/") && (strings.HasSuffix(rel, ".java") || strings.HasSuffix(rel, ".kt"))
+}
+
+// codeFence returns a Markdown code fence longer than any run of backticks in src, so the source
+// can't close it early.
+func codeFence(src string) string {
+	longest, run := 0, 0
+	for _, r := range src {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// anchor turns a file path into an HTML id / Markdown heading link.
+func anchor(rel string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, rel)
+}
+
+// shortSourcePath drops the common Java package directories so listings stay readable.
+func shortSourcePath(rel string) string {
+	for _, prefix := range []string{"TeamCode/src/main/java/", "TeamCode/src/main/kotlin/", "TeamCode/src/"} {
+		if strings.HasPrefix(rel, prefix) {
+			return strings.TrimPrefix(rel, prefix)
+		}
+	}
+	return rel
+}
+
+// writeListing renders a printable code listing of the TeamCode sources as "md" or "html".
+func writeListing(w io.Writer, format, projectPath string, files []string, info ExportInfo) error {
+	type source struct {
+		rel, text string
+	}
+	var sources []source
+	var opmodes []OpModeEntry
+	for _, rel := range files {
+		if !isSourceFile(rel) {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source{rel, string(b)})
+		opmodes = append(opmodes, findOpModes(rel, string(b))...)
+	}
+	sort.SliceStable(opmodes, func(i, j int) bool {
+		if opmodes[i].Kind != opmodes[j].Kind {
+			return opmodes[i].Kind == "Autonomous"
+		}
+		return opmodes[i].Name < opmodes[j].Name
+	})
+
+	disabled := func(e OpModeEntry) string {
+		if e.Disabled {
+			return " (disabled)"
+		}
+		return ""
+	}
+
+	var b strings.Builder
+	switch format {
+	case "md", "markdown":
+		fmt.Fprintf(&b, "# %s\n\n", info.Project)
+		for _, line := range strings.Split(strings.TrimSpace(info.String()), "\n") {
+			fmt.Fprintf(&b, "    %s\n", line)
+		}
+		b.WriteString("\n## OpModes\n\n")
+		if len(opmodes) == 0 {
+			b.WriteString("No OpModes found.\n")
+		}
+		for _, e := range opmodes {
+			fmt.Fprintf(&b, "- %s **%s**%s: [%s](#%s)\n", e.Kind, e.Name, disabled(e), shortSourcePath(e.File), anchor(e.File))
+		}
+		b.WriteString("\n## Files\n\n")
+		for _, s := range sources {
+			fmt.Fprintf(&b, "- [%s](#%s)\n", shortSourcePath(s.rel), anchor(s.rel))
+		}
+		for _, s := range sources {
+			lang := "java"
+			if strings.HasSuffix(s.rel, ".kt") {
+				lang = "kotlin"
+			}
+			fence := codeFence(s.text)
+			fmt.Fprintf(&b, "\n<a id=\"%s\"></a>\n\n### %s\n\n%s%s\n%s\n%s\n", anchor(s.rel), shortSourcePath(s.rel), fence, lang, strings.TrimRight(s.text, "\n"), fence)
+		}
+	case "html":
+		title := html.EscapeString(info.Project)
+		fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", title)
+		b.WriteString("<style>body{font-family:sans-serif;margin:2em}pre{font-size:9pt;border:1px solid #ccc;padding:.5em;white-space:pre-wrap}h3{page-break-before:always}</style>\n</head>\n<body>\n")
+		fmt.Fprintf(&b, "<h1>%s</h1>\n<pre>%s</pre>\n<h2>OpModes</h2>\n<ul>\n", title, html.EscapeString(info.String()))
+		if len(opmodes) == 0 {
+			b.WriteString("<li>No OpModes found.</li>\n")
+		}
+		for _, e := range opmodes {
+			fmt.Fprintf(&b, "<li>%s <b>%s</b>%s: <a href=\"#%s\">%s</a></li>\n", e.Kind, html.EscapeString(e.Name), disabled(e), anchor(e.File), html.EscapeString(shortSourcePath(e.File)))
+		}
+		b.WriteString("</ul>\n<h2>Files</h2>\n<ul>\n")
+		for _, s := range sources {
+			fmt.Fprintf(&b, "<li><a href=\"#%s\">%s</a></li>\n", anchor(s.rel), html.EscapeString(shortSourcePath(s.rel)))
+		}
+		b.WriteString("</ul>\n")
+		for _, s := range sources {
+			fmt.Fprintf(&b, "<h3 id=\"%s\">%s</h3>\n<pre>%s</pre>\n", anchor(s.rel), html.EscapeString(shortSourcePath(s.rel)), html.EscapeString(strings.TrimRight(s.text, "\n")))
+		}
+		b.WriteString("</body>\n</html>\n")
+	default:
+		return fmt.Errorf("unsupported listing format %q (use md or html)", format)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// export: bundle a project's sources for sharing
+var exportCmd = &cobra.Command{
+	Use:   "export [project_name]",
+	Short: "Bundle a project's TeamCode sources and Gradle files for sharing",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+		listing, _ := cmd.Flags().GetString("listing")
+
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if format == "tgz" {
+			format = "tar.gz"
+		}
+		if format != "zip" && format != "tar.gz" {
+			fmt.Printf("Unsupported format '%s'. Use zip or tar.gz.\n", format)
+			return
+		}
+		if listing != "" && listing != "md" && listing != "html" {
+			fmt.Printf("Unsupported listing format '%s'. Use md or html.\n", listing)
+			return
+		}
+
+		files, err := exportFiles(projectPath)
+		if err != nil {
+			fmt.Println("Error collecting project files:", err)
+			return
+		}
+		info := exportInfo(args[0], projectPath, teamCodeDir(args[0]))
+		if strings.HasSuffix(info.Commit, "-dirty") {
+			fmt.Println("Warning: TeamCode has uncommitted changes; the export will not match any commit.")
+		}
+
+		if out == "" {
+			out = fmt.Sprintf("%s-%s.%s", args[0], info.Exported.Format("20060102"), format)
+		}
+		if err := writeExport(out, format, projectPath, files, info); err != nil {
+			fmt.Println("Error writing export:", err)
+			return
+		}
+		fmt.Printf("Exported %d files to %s\n", len(files), out)
+
+		if listing != "" {
+			listingPath := strings.TrimSuffix(strings.TrimSuffix(out, ".zip"), ".tar.gz") + "-listing." + listing
+			f, err := os.Create(listingPath)
+			if err != nil {
+				fmt.Println("Error creating code listing:", err)
+				return
+			}
+			err = writeListing(f, listing, projectPath, files, info)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				fmt.Println("Error writing code listing:", err)
+				return
+			}
+			fmt.Println("Code listing written to", listingPath)
+		}
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringP("format", "f", "zip", "Archive format: zip or tar.gz")
+	exportCmd.Flags().StringP("out", "o", "", "Output file (default <project>-<date>.<format> in the current directory)")
+	exportCmd.Flags().String("listing", "", "Also write a printable code listing: md or html")
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsSecretFile(t *testing.T) {
+	tests := map[string]bool{
+		"local.properties":  true,
+		"release.jks":       true,
+		"upload.keystore":   true,
+		".env":              true,
+		".env.local":        true,
+		"Drive.java":        false,
+		"build.gradle":      false,
+		"KeyboardTest.java": false,
+	}
+	for name, want := range tests {
+		if got := isSecretFile(name); got != want {
+			t.Errorf("isSecretFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestExportFiles(t *testing.T) {
+	root := makeTestProject(t, t.TempDir(), "robot")
+	for _, p := range []string{"TeamCode/build.gradle", "TeamCode/release.jks", "TeamCode/src/main/java/org/firstinspires/ftc/teamcode/.git/HEAD", "FtcRobotController/build.gradle"} {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		os.MkdirAll(filepath.Dir(full), 0755)
+		os.WriteFile(full, []byte("x"), 0644)
+	}
+
+	files, err := exportFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"TeamCode/build.gradle",
+		"TeamCode/src/main/java/org/firstinspires/ftc/teamcode/Drive.java",
+		"build.gradle",
+	}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("exportFiles = %v, want %v", files, want)
+	}
+}
+
+func TestWriteExport(t *testing.T) {
+	dir := t.TempDir()
+	root := makeTestProject(t, dir, "robot")
+	files := []string{"build.gradle", "TeamCode/src/main/java/org/firstinspires/ftc/teamcode/Drive.java"}
+	info := ExportInfo{Project: "robot", SDKVersion: "v10.1", Commit: "abc123", Exported: time.Now()}
+
+	for _, format := range []string{"zip", "tar.gz"} {
+		out := filepath.Join(dir, "robot."+format)
+		if err := writeExport(out, format, root, files, info); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		contents := map[string]string{}
+		if format == "zip" {
+			zr, err := zip.OpenReader(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range zr.File {
+				rc, _ := f.Open()
+				b, _ := io.ReadAll(rc)
+				rc.Close()
+				contents[f.Name] = string(b)
+			}
+			zr.Close()
+		} else {
+			f, _ := os.Open(out)
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tr := tar.NewReader(gz)
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				b, _ := io.ReadAll(tr)
+				contents[hdr.Name] = string(b)
+			}
+			f.Close()
+		}
+
+		var names []string
+		for n := range contents {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		want := []string{"robot/EXPORT.txt", "robot/TeamCode/src/main/java/org/firstinspires/ftc/teamcode/Drive.java", "robot/build.gradle"}
+		if strings.Join(names, ",") != strings.Join(want, ",") {
+			t.Errorf("%s entries = %v, want %v", format, names, want)
+		}
+		if txt := contents["robot/EXPORT.txt"]; !strings.Contains(txt, "v10.1") || !strings.Contains(txt, "abc123") {
+			t.Errorf("%s EXPORT.txt = %q", format, txt)
+		}
+	}
+}
+
+func TestFindOpModes(t *testing.T) {
+	src := `@TeleOp(name = "Driver Control", group = "Main")
+@Disabled
+public class Drive extends LinearOpMode {}`
+	got := findOpModes("TeamCode/src/main/java/Drive.java", src)
+	if len(got) != 1 || got[0].Name != "Driver Control" || got[0].Kind != "TeleOp" || !got[0].Disabled {
+		t.Errorf("findOpModes = %+v", got)
+	}
+
+	got = findOpModes("TeamCode/src/main/java/Auto.kt", "@Autonomous\nclass Auto : LinearOpMode()")
+	if len(got) != 1 || got[0].Name != "Auto" || got[0].Kind != "Autonomous" {
+		t.Errorf("findOpModes = %+v", got)
+	}
+
+	// @Disabled only applies to the class it annotates, and a commented-out one doesn't count.
+	src = `import com.qualcomm.robotcore.eventloop.opmode.Disabled;
+
+public class Autos {
+    @Disabled
+    @Autonomous(name = "Old Left")
+    public static class OldLeft extends LinearOpMode {}
+
+    // @Disabled
+    @Autonomous
+    public static class Right extends LinearOpMode {}
+}`
+	got = findOpModes("TeamCode/src/main/java/Autos.java", src)
+	if len(got) != 2 || got[0].Name != "Old Left" || !got[0].Disabled || got[1].Name != "Right" || got[1].Disabled {
+		t.Errorf("findOpModes = %+v", got)
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"int x = 1;", "```"},
+		{"String s = \"``\";", "```"},
+		{"// ```java\n// example\n// ```", "````"},
+		{"`````", "``````"},
+	}
+	for _, tt := range tests {
+		if got := codeFence(tt.src); got != tt.want {
+			t.Errorf("codeFence(%q) = %s, want %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestWriteListing(t *testing.T) {
+	root := makeTestProject(t, t.TempDir(), "robot")
+	rel := "TeamCode/src/main/java/org/firstinspires/ftc/teamcode/Drive.java"
+	os.WriteFile(filepath.Join(root, filepath.FromSlash(rel)), []byte("@TeleOp(name=\"Drive <1>\")\nclass Drive {}\n"), 0644)
+	info := ExportInfo{Project: "robot", Exported: time.Now()}
+
+	var md strings.Builder
+	if err := writeListing(&md, "md", root, []string{"build.gradle", rel}, info); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"TeleOp **Drive <1>**", "```java\n@TeleOp", "org/firstinspires/ftc/teamcode/Drive.java"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown listing missing %q:\n%s", want, md.String())
+		}
+	}
+	if strings.Contains(md.String(), "// root") {
+		t.Error("listing should only include TeamCode sources")
+	}
+
+	var h strings.Builder
+	if err := writeListing(&h, "html", root, []string{rel}, info); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(h.String(), "Drive &lt;1&gt;") || strings.Contains(h.String(), "Drive <1>") {
+		t.Errorf("html listing not escaped:\n%s", h.String())
+	}
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Formatter is a jar-based source formatter for one file type.
+type Formatter struct {
+	Name       string
+	Ext        string
+	VersionKey string // config key overriding Default
+	Default    string
+	URL        string // download URL, with %[1]s for the version
+	// Checksum looks up the SHA-256 the publisher lists for a version.
+	Checksum  func(f Formatter, version string) (string, error)
+	JVMArgs   []string
+	CheckArgs []string // list files that would change and exit non-zero instead of rewriting
+	WriteArgs []string
+}
+
+var formatters = []Formatter{
+	{
+		Name:       "google-java-format",
+		Ext:        ".java",
+		VersionKey: "google_java_format_version",
+		Default:    "1.24.0",
+		URL:        "https://github.com/google/google-java-format/releases/download/v%[1]s/google-java-format-%[1]s-all-deps.jar",
+		// google-java-format uses javac internals, which JDK 16+ only exposes when asked.
+		JVMArgs: []string{
+			"--add-exports=jdk.compiler/com.sun.tools.javac.api=ALL-UNNAMED",
+			"--add-exports=jdk.compiler/com.sun.tools.javac.code=ALL-UNNAMED",
+			"--add-exports=jdk.compiler/com.sun.tools.javac.file=ALL-UNNAMED",
+			"--add-exports=jdk.compiler/com.sun.tools.javac.parser=ALL-UNNAMED",
+			"--add-exports=jdk.compiler/com.sun.tools.javac.tree=ALL-UNNAMED",
+			"--add-exports=jdk.compiler/com.sun.tools.javac.util=ALL-UNNAMED",
+		},
+		CheckArgs: []string{"--dry-run", "--set-exit-if-changed"},
+		WriteArgs: []string{"--replace"},
+		Checksum:  githubJarChecksum,
+	},
+	{
+		Name:       "ktfmt",
+		Ext:        ".kt",
+		VersionKey: "ktfmt_version",
+		Default:    "0.53",
+		URL:        "https://repo1.maven.org/maven2/com/facebook/ktfmt/%[1]s/ktfmt-%[1]s-jar-with-dependencies.jar",
+		CheckArgs:  []string{"--dry-run", "--set-exit-if-changed"},
+		Checksum:   mavenJarChecksum,
+	},
+}
+
+func (f Formatter) version() string {
+	return valueOr(viper.GetString(f.VersionKey), f.Default)
+}
+
+// githubJarChecksum returns the digest GitHub reports for the jar attached to a google-java-format release.
+func githubJarChecksum(f Formatter, version string) (string, error) {
+	name := path.Base(fmt.Sprintf(f.URL, version))
+	var r Release
+	if err := githubDo("GET", "/repos/google/google-java-format/releases/tags/v"+version, nil, &r); err != nil {
+		return "", err
+	}
+	for _, a := range r.Assets {
+		if a.Name != name {
+			continue
+		}
+		if d := strings.TrimPrefix(a.Digest, "sha256:"); sha256Hex.MatchString(d) {
+			return strings.ToLower(d), nil
+		}
+		return "", fmt.Errorf("GitHub reports no checksum for %s", name)
+	}
+	return "", fmt.Errorf("release v%s has no asset %s", version, name)
+}
+
+// mavenJarChecksum reads the .sha256 file Maven Central keeps next to the jar.
+func mavenJarChecksum(f Formatter, version string) (string, error) {
+	resp, err := http.Get(fmt.Sprintf(f.URL, version) + ".sha256")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading checksum: status %d", resp.StatusCode)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	// The file holds the bare checksum, sometimes followed by the file name.
+	if fields := strings.Fields(string(b)); len(fields) > 0 && sha256Hex.MatchString(fields[0]) {
+		return strings.ToLower(fields[0]), nil
+	}
+	return "", fmt.Errorf("unexpected checksum file %q", strings.TrimSpace(string(b)))
+}
+
+// checksum returns the SHA-256 the publisher lists for the jar of version. A jar is never run without one.
+func (f Formatter) checksum(version string) (string, error) {
+	if f.Checksum == nil {
+		return "", fmt.Errorf("no checksum known for %s %s", f.Name, version)
+	}
+	sum, err := f.Checksum(f, version)
+	if err != nil {
+		return "", fmt.Errorf("looking up the checksum of %s %s: %w", f.Name, version, err)
+	}
+	return sum, nil
+}
+
+// jar returns the cached formatter jar, downloading and verifying it on first use. The verified
+// checksum is kept next to the jar, and the jar is checked against it before every run.
+func (f Formatter) jar() (string, error) {
+	version := f.version()
+	if err := validReleaseTag(version); err != nil {
+		return "", fmt.Errorf("%s version: %w", f.Name, err)
+	}
+	dir := filepath.Join(cacheDir(), "formatters")
+	p := filepath.Join(dir, fmt.Sprintf("%s-%s.jar", f.Name, version))
+	b, _ := os.ReadFile(p + ".sha256")
+	if want := strings.TrimSpace(string(b)); sha256Hex.MatchString(want) {
+		if got, err := fileSHA256(p); err == nil && got == want {
+			return p, nil
+		}
+	}
+
+	want, err := f.checksum(version)
+	if err != nil {
+		return "", fmt.Errorf("%w; refusing to run an unverified jar", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	fmt.Printf("Downloading %s %s...\n", f.Name, version)
+	if err := downloadVerified(fmt.Sprintf(f.URL, version), p+".part", want); err != nil {
+		return "", fmt.Errorf("downloading %s: %w", f.Name, err)
+	}
+	if err := os.WriteFile(p+".sha256", []byte(want+"\n"), 0644); err != nil {
+		os.Remove(p + ".part")
+		return "", err
+	}
+	return p, os.Rename(p+".part", p)
+}
+
+// fileSHA256 returns the hex SHA-256 of a file.
+func fileSHA256(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// formatSources returns the Java and Kotlin files below dir, grouped by extension. Files written by
+// hwconfig codegen are left alone so regenerating them doesn't fight the formatter.
+func formatSources(dir string) (map[string][]string, error) {
+	files := map[string][]string{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := filepath.Ext(p)
+		if ext != ".java" && ext != ".kt" {
+			return nil
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(string(b), generatedMarker) {
+			return nil
+		}
+		files[ext] = append(files[ext], p)
+		return nil
+	})
+	return files, err
+}
+
+// runJava runs java with args and returns its stdout. A variable so tests can fake the formatter.
+var runJava = func(java string, args []string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(java, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil && stderr.Len() > 0 {
+		return out, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, err
+}
+
+// formatFiles runs the formatter over files. In check mode nothing is written. It returns the files
+// that are (or, in check mode, would be) changed.
+func formatFiles(java, jar string, f Formatter, files []string, check bool) ([]string, error) {
+	before := map[string][]byte{}
+	for _, p := range files {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		before[p] = b
+	}
+
+	args := append(append([]string{}, f.JVMArgs...), "-jar", jar)
+	if check {
+		args = append(args, f.CheckArgs...)
+	} else {
+		args = append(args, f.WriteArgs...)
+	}
+	out, err := runJava(java, append(args, files...))
+
+	var changed []string
+	if check {
+		// Both formatters print the name of every file that isn't formatted and exit 1 if there are any.
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				changed = append(changed, line)
+			}
+		}
+		if err != nil && len(changed) == 0 {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		return changed, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	for _, p := range files {
+		if b, err := os.ReadFile(p); err == nil && !bytes.Equal(b, before[p]) {
+			changed = append(changed, p)
+		}
+	}
+	return changed, nil
+}
+
+// formatProject formats (or checks) the teamcode package of a project and returns the changed files.
+func formatProject(projectName string, check bool) ([]string, error) {
+	if _, err := existingProject(projectName); err != nil {
+		return nil, err
+	}
+	sources, err := formatSources(teamCodeDir(projectName))
+	if err != nil {
+		return nil, err
+	}
+	java, err := findJava()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, f := range formatters {
+		files := sources[f.Ext]
+		if len(files) == 0 {
+			continue
+		}
+		jar, err := f.jar()
+		if err != nil {
+			return nil, err
+		}
+		c, err := formatFiles(java, jar, f, files, check)
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, c...)
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// fmt: format TeamCode sources
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [project_name]",
+	Short: "Format the TeamCode sources with google-java-format and ktfmt",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		check, _ := cmd.Flags().GetBool("check")
+		changed, err := formatProject(args[0], check)
+		if err != nil {
+			fmt.Println("Error:", err)
+			if check {
+				os.Exit(1)
+			}
+			return
+		}
+		teamCode := teamCodeDir(args[0])
+		for _, p := range changed {
+			if rel, err := filepath.Rel(teamCode, p); err == nil {
+				p = rel
+			}
+			fmt.Println(p)
+		}
+		switch {
+		case check && len(changed) > 0:
+			fmt.Printf("%d file(s) need formatting. Run 'ftc-helper fmt %s'.\n", len(changed), args[0])
+			os.Exit(1)
+		case check:
+			fmt.Println("All files are formatted.")
+		case len(changed) > 0:
+			fmt.Printf("Formatted %d file(s).\n", len(changed))
+		default:
+			fmt.Println("Nothing to format.")
+		}
+	},
+}
+
+func init() {
+	fmtCmd.Flags().Bool("check", false, "Only report files that need formatting; exit 1 if there are any")
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// fakeFormatter stands in for java: it upper-cases files that contain "unformatted".
+func fakeFormatter(t *testing.T) *[][]string {
+	t.Helper()
+	var calls [][]string
+	old := runJava
+	t.Cleanup(func() { runJava = old })
+	runJava = func(java string, args []string) ([]byte, error) {
+		calls = append(calls, args)
+		check := false
+		var out strings.Builder
+		for _, a := range args {
+			if a == "--dry-run" {
+				check = true
+			}
+			b, err := os.ReadFile(a)
+			if err != nil || !strings.Contains(string(b), "unformatted") {
+				continue
+			}
+			if check {
+				out.WriteString(a + "\n")
+			} else {
+				os.WriteFile(a, []byte(strings.ToUpper(string(b))), 0644)
+			}
+		}
+		return []byte(out.String()), nil
+	}
+	return &calls
+}
+
+func TestFormatFiles(t *testing.T) {
+	calls := fakeFormatter(t)
+	dir := t.TempDir()
+	good := filepath.Join(dir, "Good.java")
+	bad := filepath.Join(dir, "Bad.java")
+	os.WriteFile(good, []byte("class Good {}"), 0644)
+	os.WriteFile(bad, []byte("class Bad { unformatted }"), 0644)
+	gjf := formatters[0]
+
+	changed, err := formatFiles("java", "gjf.jar", gjf, []string{good, bad}, true)
+	if err != nil || !reflect.DeepEqual(changed, []string{bad}) {
+		t.Errorf("check = %v, %v; want [%s]", changed, err, bad)
+	}
+	if b, _ := os.ReadFile(bad); strings.Contains(string(b), "UNFORMATTED") {
+		t.Error("check mode rewrote a file")
+	}
+	if args := (*calls)[0]; !strings.HasPrefix(args[0], "--add-exports") || args[len(gjf.JVMArgs)] != "-jar" {
+		t.Errorf("java args = %v", args)
+	}
+
+	changed, err = formatFiles("java", "gjf.jar", gjf, []string{good, bad}, false)
+	if err != nil || !reflect.DeepEqual(changed, []string{bad}) {
+		t.Errorf("format = %v, %v; want [%s]", changed, err, bad)
+	}
+	if args := (*calls)[1]; !contains(args, "--replace") {
+		t.Errorf("java args = %v, want --replace", args)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFormatSources(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "auto", "build"), 0755)
+	os.WriteFile(filepath.Join(dir, "Drive.java"), []byte("class Drive {}"), 0644)
+	os.WriteFile(filepath.Join(dir, "auto", "Path.kt"), []byte("class Path"), 0644)
+	os.WriteFile(filepath.Join(dir, "auto", "build", "Gen.java"), []byte("class Gen {}"), 0644)
+	os.WriteFile(filepath.Join(dir, "RobotHardware.java"), []byte(generatedMarker+"\nclass RobotHardware {}"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644)
+
+	files, err := formatSources(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A package named build is source like any other; Gradle never writes inside the teamcode package.
+	if len(files[".java"]) != 2 || len(files[".kt"]) != 1 {
+		t.Errorf("formatSources = %v", files)
+	}
+}
+
+func TestFormatterJarCached(t *testing.T) {
+	jar := []byte("jar")
+	sum := sha256.Sum256(jar)
+	var downloads int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ktfmt-0.1.jar":
+			downloads++
+			w.Write(jar)
+		case "/ktfmt-0.1.jar.sha256":
+			w.Write([]byte(hex.EncodeToString(sum[:])))
+		case "/ktfmt-0.3.jar":
+			w.Write([]byte("other jar"))
+		case "/ktfmt-0.3.jar.sha256":
+			w.Write([]byte(strings.Repeat("0", 64) + "  ktfmt-0.3.jar\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	viper.Set("cache_dir", t.TempDir())
+	viper.Set("ktfmt_version", "0.1")
+	defer viper.Set("cache_dir", "")
+	defer viper.Set("ktfmt_version", "")
+
+	f := formatters[1]
+	f.URL = srv.URL + "/ktfmt-%[1]s.jar"
+	for i := 0; i < 2; i++ {
+		p, err := f.jar()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(p) != "ktfmt-0.1.jar" {
+			t.Errorf("jar = %s", p)
+		}
+	}
+	if downloads != 1 {
+		t.Errorf("downloaded %d times, want 1", downloads)
+	}
+
+	// A cached jar that no longer matches its checksum is downloaded again.
+	p, _ := f.jar()
+	os.WriteFile(p, []byte("tampered"), 0644)
+	if _, err := f.jar(); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(p); string(b) != "jar" || downloads != 2 {
+		t.Errorf("after tampering: jar = %q, %d downloads", b, downloads)
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		wantErr string
+	}{
+		{"checksum mismatch", "0.3", "checksum mismatch"},
+		{"no published checksum", "0.2", "refusing to run an unverified jar"},
+		{"path in version", "../0.1", "invalid release tag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("cache_dir", t.TempDir())
+			viper.Set("ktfmt_version", tt.version)
+			if _, err := f.jar(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("jar() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGitHubJarChecksum(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	fakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/google/google-java-format/releases/tags/v1.2.0":
+			w.Write([]byte(`{"tag_name":"v1.2.0","assets":[{"name":"google-java-format-1.2.0-all-deps.jar","digest":"sha256:` + sum + `"}]}`))
+		case "/repos/google/google-java-format/releases/tags/v1.1.0":
+			w.Write([]byte(`{"tag_name":"v1.1.0","assets":[{"name":"google-java-format-1.1.0-all-deps.jar","digest":null}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	gjf := formatters[0]
+	if got, err := gjf.checksum("1.2.0"); err != nil || got != sum {
+		t.Errorf("checksum(1.2.0) = %q, %v; want %q", got, err, sum)
+	}
+	if _, err := gjf.checksum("1.1.0"); err == nil || !strings.Contains(err.Error(), "no checksum") {
+		t.Errorf("checksum(1.1.0) error = %v, want no checksum", err)
+	}
+}
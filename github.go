@@ -0,0 +1,407 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultGitHubAPIURL = "https://api.github.com"
+	defaultGitHubURL    = "https://github.com"
+)
+
+// githubAPIURL returns the REST API base URL. It can be overridden with
+// github_api_url so the tool can be pointed at a local fake for testing.
+func githubAPIURL() string {
+	if v := viper.GetString("github_api_url"); v != "" {
+		return strings.TrimRight(v, "/")
+	}
+	return defaultGitHubAPIURL
+}
+
+// githubWebURL returns the base URL used for the OAuth device flow (github_url).
+func githubWebURL() string {
+	if v := viper.GetString("github_url"); v != "" {
+		return strings.TrimRight(v, "/")
+	}
+	return defaultGitHubURL
+}
+
+// githubToken returns the token used to authenticate GitHub requests, or "" if none is set.
+// Order: github_token (config file or GITHUB_TOKEN env var), GH_TOKEN env var, token saved by `auth login`.
+func githubToken() string {
+	if t := viper.GetString("github_token"); t != "" {
+		return t
+	}
+	if t := os.Getenv("GH_TOKEN"); t != "" {
+		return t
+	}
+	if t, err := loadGitHubToken(); err == nil {
+		return t
+	}
+	return ""
+}
+
+// githubTokenPath returns the file used to store the token obtained with `auth login`.
+func githubTokenPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ftc-helper", "github-token"), nil
+}
+
+func loadGitHubToken() (string, error) {
+	p, err := githubTokenPath()
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return "", err
+	}
+	t := strings.TrimSpace(string(b))
+	if t == "" {
+		return "", errors.New("stored token is empty")
+	}
+	return t, nil
+}
+
+// saveGitHubToken writes the token to a file only readable by the current user.
+func saveGitHubToken(token string) error {
+	p, err := githubTokenPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(p, []byte(token+"\n"), 0600)
+}
+
+// githubClient is used for GitHub API and OAuth requests, so a dead network fails instead of hanging.
+var githubClient = &http.Client{Timeout: 30 * time.Second}
+
+// deviceLoginPause waits between device flow polls. A variable so tests don't sleep.
+var deviceLoginPause = time.Sleep
+
+// newGitHubRequest builds a request against the GitHub REST API, adding the
+// Authorization header when a token is available. endpoint is relative to the API base, e.g. "/user".
+func newGitHubRequest(method, endpoint string, body interface{}) (*http.Request, error) {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, githubAPIURL()+endpoint, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if t := githubToken(); t != "" {
+		req.Header.Set("Authorization", "Bearer "+t)
+	}
+	return req, nil
+}
+
+// githubDo performs a GitHub API call and decodes the JSON response into out (if non-nil).
+func githubDo(method, endpoint string, body, out interface{}) error {
+	req, err := newGitHubRequest(method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	resp, err := githubClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(b, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("%s %s: %s (status %d)", method, endpoint, apiErr.Message, resp.StatusCode)
+		}
+		return fmt.Errorf("%s %s: unexpected status code: %d", method, endpoint, resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(b, out)
+}
+
+// GitHubRepo is the subset of the repository object returned by the API that we use.
+type GitHubRepo struct {
+	FullName string `json:"full_name"`
+	CloneURL string `json:"clone_url"`
+	SSHURL   string `json:"ssh_url"`
+	HTMLURL  string `json:"html_url"`
+}
+
+// createGitHubRepo creates owner/name. If owner is the authenticated user the repo is
+// created under their account, otherwise owner is treated as an organization.
+func createGitHubRepo(owner, name string, private bool) (*GitHubRepo, error) {
+	if githubToken() == "" {
+		return nil, errors.New("no GitHub token found. Run 'ftc-helper auth login' or set GITHUB_TOKEN")
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := githubDo("GET", "/user", nil, &user); err != nil {
+		return nil, err
+	}
+
+	endpoint := "/user/repos"
+	if !strings.EqualFold(owner, user.Login) {
+		endpoint = "/orgs/" + url.PathEscape(owner) + "/repos"
+	}
+
+	body := map[string]interface{}{
+		"name":        name,
+		"private":     private,
+		"description": "FTC robot code",
+	}
+	var repo GitHubRepo
+	if err := githubDo("POST", endpoint, body, &repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// splitRepoSlug splits "owner/name" into its parts.
+func splitRepoSlug(slug string) (string, string, error) {
+	parts := strings.Split(strings.Trim(slug, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid repository %q, expected owner/name", slug)
+	}
+	return parts[0], strings.TrimSuffix(parts[1], ".git"), nil
+}
+
+// gitAuthEnv returns environment variables that make git send token as an extra HTTP header to the
+// host of an https remote. Unlike -c on the command line, the environment isn't visible to other
+// users in the process list.
+func gitAuthEnv(remoteURL, token string) []string {
+	if token == "" || !strings.HasPrefix(remoteURL, "https://") {
+		return nil
+	}
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return nil
+	}
+	cred := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		fmt.Sprintf("GIT_CONFIG_KEY_0=http.https://%s/.extraheader", u.Host),
+		"GIT_CONFIG_VALUE_0=AUTHORIZATION: basic " + cred,
+	}
+}
+
+// pushInitialCommit commits everything in dir and pushes it to origin. When a GitHub
+// token is available it is passed as an extra HTTP header so it never ends up in .git/config.
+func pushInitialCommit(dir, remoteURL string) error {
+	cmdAdd := exec.Command("git", "add", ".")
+	cmdAdd.Dir = dir
+	if err := cmdAdd.Run(); err != nil {
+		return fmt.Errorf("staging files: %w", err)
+	}
+
+	commitArgs := append(teamProfile().gitIdentityArgs(), "commit", "-m", "Initial commit")
+	cmdCommit := exec.Command("git", commitArgs...)
+	cmdCommit.Dir = dir
+	if out, err := cmdCommit.CombinedOutput(); err != nil {
+		return fmt.Errorf("committing: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	cmdPush := exec.Command("git", "push", "-u", "origin", "HEAD")
+	cmdPush.Dir = dir
+	if env := gitAuthEnv(remoteURL, githubToken()); env != nil {
+		cmdPush.Env = append(os.Environ(), env...)
+	}
+	cmdPush.Stdout = os.Stdout
+	cmdPush.Stderr = os.Stderr
+	if err := cmdPush.Run(); err != nil {
+		return fmt.Errorf("pushing: %w", err)
+	}
+	return nil
+}
+
+// githubDeviceLogin runs the OAuth device flow and returns the access token.
+// See https://docs.github.com/en/apps/oauth-apps/building-oauth-apps/authorizing-oauth-apps#device-flow
+func githubDeviceLogin(clientID string, out io.Writer) (string, error) {
+	var code struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURI string `json:"verification_uri"`
+		ExpiresIn       int    `json:"expires_in"`
+		Interval        int    `json:"interval"`
+	}
+	err := postForm(githubWebURL()+"/login/device/code", url.Values{
+		"client_id": {clientID},
+		"scope":     {"repo"},
+	}, &code)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(out, "Open %s and enter the code: %s\n", code.VerificationURI, code.UserCode)
+
+	// GitHub asks for at least 5s between polls, and 5s more after each slow_down.
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		deviceLoginPause(interval)
+
+		var tok struct {
+			AccessToken string `json:"access_token"`
+			Error       string `json:"error"`
+		}
+		err := postForm(githubWebURL()+"/login/oauth/access_token", url.Values{
+			"client_id":   {clientID},
+			"device_code": {code.DeviceCode},
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		}, &tok)
+		if err != nil {
+			return "", err
+		}
+
+		switch tok.Error {
+		case "":
+			if tok.AccessToken == "" {
+				return "", errors.New("no access token in response")
+			}
+			return tok.AccessToken, nil
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return "", fmt.Errorf("device login failed: %s", tok.Error)
+		}
+	}
+	return "", errors.New("device code expired before authorization completed")
+}
+
+// postForm posts a form and decodes a JSON response.
+func postForm(u string, form url.Values, out interface{}) error {
+	req, err := http.NewRequest("POST", u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := githubClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// auth: manage the GitHub token used for API requests and repository creation
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage GitHub authentication",
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in to GitHub using the device flow and store the token",
+	Run: func(cmd *cobra.Command, args []string) {
+		clientID := viper.GetString("github_client_id")
+		if clientID == "" {
+			fmt.Println("No OAuth client ID configured. Set github_client_id in the config file or GITHUB_CLIENT_ID.")
+			return
+		}
+
+		token, err := githubDeviceLogin(clientID, os.Stdout)
+		if err != nil {
+			fmt.Println("Error logging in:", err)
+			return
+		}
+		if err := saveGitHubToken(token); err != nil {
+			fmt.Println("Error saving token:", err)
+			return
+		}
+		fmt.Println("Logged in to GitHub.")
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored GitHub token",
+	Run: func(cmd *cobra.Command, args []string) {
+		p, err := githubTokenPath()
+		if err != nil {
+			fmt.Println("Error locating token file:", err)
+			return
+		}
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			fmt.Println("Error removing token:", err)
+			return
+		}
+		fmt.Println("Logged out of GitHub.")
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the GitHub account used for API requests",
+	Run: func(cmd *cobra.Command, args []string) {
+		if githubToken() == "" {
+			fmt.Println("Not logged in. Requests to GitHub are unauthenticated.")
+			return
+		}
+		var user struct {
+			Login string `json:"login"`
+		}
+		if err := githubDo("GET", "/user", nil, &user); err != nil {
+			fmt.Println("Error checking token:", err)
+			return
+		}
+		fmt.Println("Logged in to GitHub as", user.Login)
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	authCmd.AddCommand(authStatusCmd)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const gradleMarker = "// ftc-helper:lib="
+
+var gradleMarkerRe = regexp.MustCompile(`// ftc-helper:lib=([\w\-.]+)`)
+
+// addLibraryToGradle adds the library's repositories and dependencies to the contents of
+// build.dependencies.gradle. Lines are tagged with a marker comment so running it again
+// replaces the previous entries instead of duplicating them.
+func addLibraryToGradle(content string, lib Library) (string, error) {
+	content = removeMarkedLines(content, lib.Name)
+	marker := gradleMarker + lib.Name
+
+	var repos []string
+	for _, r := range lib.Repositories {
+		if !strings.Contains(content, r) {
+			repos = append(repos, fmt.Sprintf("    maven { url = '%s' } %s", r, marker))
+		}
+	}
+	var deps []string
+	for _, d := range lib.coordinates() {
+		deps = append(deps, fmt.Sprintf("    implementation '%s' %s", d, marker))
+	}
+
+	var err error
+	if len(repos) > 0 {
+		if content, err = appendToGradleBlock(content, "repositories", repos); err != nil {
+			return "", err
+		}
+	}
+	return appendToGradleBlock(content, "dependencies", deps)
+}
+
+// removeLibraryFromGradle removes everything tagged for the library. Repositories that
+// other installed libraries still need are added back for them.
+func removeLibraryFromGradle(content string, name string) (string, error) {
+	content = removeMarkedLines(content, name)
+	for _, other := range installedGradleLibraries(content) {
+		lib, err := lookupLibrary(other)
+		if err != nil {
+			continue
+		}
+		var repos []string
+		for _, r := range lib.Repositories {
+			if !strings.Contains(content, r) {
+				repos = append(repos, fmt.Sprintf("    maven { url = '%s' } %s%s", r, gradleMarker, lib.Name))
+			}
+		}
+		if len(repos) > 0 {
+			var err error
+			if content, err = appendToGradleBlock(content, "repositories", repos); err != nil {
+				return "", err
+			}
+		}
+	}
+	return content, nil
+}
+
+// installedGradleLibraries returns the names of libraries added by ftc-helper.
+func installedGradleLibraries(content string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, m := range gradleMarkerRe.FindAllStringSubmatch(content, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func removeMarkedLines(content, name string) string {
+	marker := gradleMarker + name
+	lines := strings.Split(content, "\n")
+	out := lines[:0]
+	for _, l := range lines {
+		if strings.HasSuffix(strings.TrimRight(l, " \r"), marker) {
+			continue
+		}
+		out = append(out, l)
+	}
+	return strings.Join(out, "\n")
+}
+
+// appendToGradleBlock inserts lines just before the closing brace of the first top-level
+// "<name> {" block, creating the block at the end of the file if it is missing.
+func appendToGradleBlock(content, name string, lines []string) (string, error) {
+	all := strings.Split(content, "\n")
+	start := -1
+	for i, l := range all {
+		t := strings.TrimSpace(l)
+		if l == strings.TrimLeft(l, " \t") && (t == name+" {" || t == name+"{") {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		block := "\n" + name + " {\n" + strings.Join(lines, "\n") + "\n}\n"
+		return strings.TrimRight(content, "\n") + "\n" + block, nil
+	}
+
+	depth := 0
+	for i := start; i < len(all); i++ {
+		depth += strings.Count(stripGradleComment(all[i]), "{") - strings.Count(stripGradleComment(all[i]), "}")
+		if depth == 0 {
+			out := append([]string{}, all[:i]...)
+			out = append(out, lines...)
+			out = append(out, all[i:]...)
+			return strings.Join(out, "\n"), nil
+		}
+	}
+	return "", fmt.Errorf("unterminated %s block", name)
+}
+
+func stripGradleComment(line string) string {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// addLibraryToProject edits the project's build.dependencies.gradle in place.
+func addLibraryToProject(projectPath string, lib Library) error {
+	return editGradleFile(filepath.Join(projectPath, "build.dependencies.gradle"), func(s string) (string, error) {
+		return addLibraryToGradle(s, lib)
+	})
+}
+
+// removeLibraryFromProject removes a library from the project's build.dependencies.gradle.
+func removeLibraryFromProject(projectPath, name string) error {
+	return editGradleFile(filepath.Join(projectPath, "build.dependencies.gradle"), func(s string) (string, error) {
+		return removeLibraryFromGradle(s, name)
+	})
+}
+
+// projectLibraries returns the libraries ftc-helper has added to the project.
+func projectLibraries(projectPath string) ([]string, error) {
+	b, err := os.ReadFile(filepath.Join(projectPath, "build.dependencies.gradle"))
+	if err != nil {
+		return nil, err
+	}
+	return installedGradleLibraries(string(b)), nil
+}
+
+func editGradleFile(p string, edit func(string) (string, error)) error {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	out, err := edit(string(b))
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(p), err)
+	}
+	if out == string(b) {
+		return nil
+	}
+	return os.WriteFile(p, []byte(out), 0644)
+}
+
+const kotlinPluginVersion = "1.9.24"
+
+// enableKotlin adds the Kotlin Gradle plugin to the root build script and applies it to TeamCode.
+func enableKotlin(projectPath string) error {
+	err := editGradleFile(filepath.Join(projectPath, "build.gradle"), func(s string) (string, error) {
+		if strings.Contains(s, "kotlin-gradle-plugin") {
+			return s, nil
+		}
+		re := regexp.MustCompile(`(?m)^(\s*)classpath 'com\.android\.tools\.build:gradle:[^']*'.*$`)
+		loc := re.FindStringSubmatchIndex(s)
+		if loc == nil {
+			return "", fmt.Errorf("could not find the Android Gradle plugin classpath")
+		}
+		indent := s[loc[2]:loc[3]]
+		line := fmt.Sprintf("\n%sclasspath 'org.jetbrains.kotlin:kotlin-gradle-plugin:%s'", indent, kotlinPluginVersion)
+		return s[:loc[1]] + line + s[loc[1]:], nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return editGradleFile(filepath.Join(projectPath, "TeamCode", "build.gradle"), func(s string) (string, error) {
+		if strings.Contains(s, "kotlin-android") {
+			return s, nil
+		}
+		anchor := "apply from: '../build.common.gradle'"
+		if !strings.Contains(s, anchor) {
+			return "apply plugin: 'kotlin-android'\n" + s, nil
+		}
+		return strings.Replace(s, anchor, anchor+"\napply plugin: 'kotlin-android'", 1), nil
+	})
+}
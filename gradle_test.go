@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testDependenciesGradle = `repositories {
+    mavenCentral()
+    google() // Needed for androidx
+}
+
+dependencies {
+    implementation 'org.firstinspires.ftc:RobotCore:10.1.0'
+    implementation 'androidx.appcompat:appcompat:1.2.0'
+}
+`
+
+func TestAddLibraryToGradle_Idempotent(t *testing.T) {
+	withoutUserLibraries(t)
+	lib, err := lookupLibrary("dashboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	once, err := addLibraryToGradle(testDependenciesGradle, lib)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	twice, err := addLibraryToGradle(once, lib)
+	if err != nil {
+		t.Fatalf("add again: %v", err)
+	}
+	if once != twice {
+		t.Fatalf("adding twice changed the file:\n%s\n---\n%s", once, twice)
+	}
+	if !strings.Contains(once, "    maven { url = 'https://maven.brott.dev/' } // ftc-helper:lib=dashboard\n}") {
+		t.Errorf("repository not added to repositories block:\n%s", once)
+	}
+	if !strings.Contains(once, "    implementation 'com.acmerobotics.dashboard:dashboard:0.4.17' // ftc-helper:lib=dashboard\n}") {
+		t.Errorf("dependency not added to dependencies block:\n%s", once)
+	}
+}
+
+func TestAddLibraryToGradle_Version(t *testing.T) {
+	withoutUserLibraries(t)
+	lib, err := lookupLibrary("ftclib@2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := addLibraryToGradle(testDependenciesGradle, lib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "org.ftclib.ftclib:core:2.0.0") {
+		t.Errorf("version override not applied:\n%s", got)
+	}
+}
+
+func TestRemoveLibraryFromGradle_KeepsSharedRepository(t *testing.T) {
+	withoutUserLibraries(t)
+	content := testDependenciesGradle
+	for _, name := range []string{"dashboard", "roadrunner"} {
+		lib, _ := lookupLibrary(name)
+		var err error
+		if content, err = addLibraryToGradle(content, lib); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := installedGradleLibraries(content); strings.Join(got, ",") != "dashboard,roadrunner" {
+		t.Fatalf("installed = %v", got)
+	}
+
+	content, err := removeLibraryFromGradle(content, "dashboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(content, "https://maven.brott.dev/") {
+		t.Errorf("repository needed by roadrunner was removed:\n%s", content)
+	}
+
+	content, err = removeLibraryFromGradle(content, "roadrunner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != testDependenciesGradle {
+		t.Errorf("removing all libraries did not restore the file:\n%s", content)
+	}
+}
+
+func TestEnableKotlin(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "TeamCode"), 0755)
+	os.WriteFile(filepath.Join(dir, "build.gradle"), []byte("buildscript {\n    dependencies {\n        classpath 'com.android.tools.build:gradle:8.7.0'\n    }\n}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "TeamCode", "build.gradle"), []byte("apply from: '../build.common.gradle'\napply from: '../build.dependencies.gradle'\n"), 0644)
+
+	for i := 0; i < 2; i++ {
+		if err := enableKotlin(dir); err != nil {
+			t.Fatalf("enableKotlin: %v", err)
+		}
+	}
+
+	root, _ := os.ReadFile(filepath.Join(dir, "build.gradle"))
+	if strings.Count(string(root), "kotlin-gradle-plugin") != 1 {
+		t.Errorf("root build.gradle:\n%s", root)
+	}
+	tc, _ := os.ReadFile(filepath.Join(dir, "TeamCode", "build.gradle"))
+	if !strings.HasPrefix(string(tc), "apply from: '../build.common.gradle'\napply plugin: 'kotlin-android'\n") {
+		t.Errorf("TeamCode build.gradle:\n%s", tc)
+	}
+}
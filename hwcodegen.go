@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// generatedMarker starts every file written by hwconfig codegen. Files without it are never overwritten.
+const generatedMarker = "// Code generated by ftc-helper hwconfig codegen. DO NOT EDIT."
+
+// javaDeviceType is the SDK class used for a configured device type.
+type javaDeviceType struct {
+	Class  string
+	Import string
+}
+
+// javaDeviceTypes maps configuration XML element names to SDK classes. Motors are matched separately.
+var javaDeviceTypes = map[string]javaDeviceType{
+	"Servo":                    {"Servo", "com.qualcomm.robotcore.hardware.Servo"},
+	"ContinuousRotationServo":  {"CRServo", "com.qualcomm.robotcore.hardware.CRServo"},
+	"ControlHubImuBHI260AP":    {"IMU", "com.qualcomm.robotcore.hardware.IMU"},
+	"LynxEmbeddedIMU":          {"IMU", "com.qualcomm.robotcore.hardware.IMU"},
+	"RevInternalBNO055IMU":     {"IMU", "com.qualcomm.robotcore.hardware.IMU"},
+	"RevColorSensorV3":         {"RevColorSensorV3", "com.qualcomm.hardware.rev.RevColorSensorV3"},
+	"REV_VL53L0X_RANGE_SENSOR": {"DistanceSensor", "com.qualcomm.robotcore.hardware.DistanceSensor"},
+	"RevTouchSensor":           {"TouchSensor", "com.qualcomm.robotcore.hardware.TouchSensor"},
+	"DigitalDevice":            {"DigitalChannel", "com.qualcomm.robotcore.hardware.DigitalChannel"},
+	"AnalogInput":              {"AnalogInput", "com.qualcomm.robotcore.hardware.AnalogInput"},
+	"Webcam":                   {"WebcamName", "org.firstinspires.ftc.robotcore.external.hardware.camera.WebcamName"},
+	"goBILDAPinpoint":          {"GoBildaPinpointDriver", "com.qualcomm.hardware.gobilda.GoBildaPinpointDriver"},
+	"SparkFunOTOS":             {"SparkFunOTOS", "com.qualcomm.hardware.sparkfun.SparkFunOTOS"},
+	"Limelight3A":              {"Limelight3A", "com.qualcomm.hardware.limelightvision.Limelight3A"},
+}
+
+// javaTypeFor returns the class for a device, falling back to HardwareDevice for unknown types.
+func javaTypeFor(d HardwareDevice) javaDeviceType {
+	if t, ok := javaDeviceTypes[d.Type]; ok {
+		return t
+	}
+	if d.Kind() == "motor" {
+		return javaDeviceType{"DcMotorEx", "com.qualcomm.robotcore.hardware.DcMotorEx"}
+	}
+	return javaDeviceType{"HardwareDevice", "com.qualcomm.robotcore.hardware.HardwareDevice"}
+}
+
+var javaKeywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true, "case": true,
+	"catch": true, "char": true, "class": true, "const": true, "continue": true, "default": true,
+	"do": true, "double": true, "else": true, "enum": true, "extends": true, "final": true,
+	"finally": true, "float": true, "for": true, "goto": true, "if": true, "implements": true,
+	"import": true, "instanceof": true, "int": true, "interface": true, "long": true, "native": true,
+	"new": true, "package": true, "private": true, "protected": true, "public": true, "return": true,
+	"short": true, "static": true, "super": true, "switch": true, "synchronized": true, "this": true,
+	"throw": true, "throws": true, "transient": true, "try": true, "void": true, "volatile": true,
+	"while": true, "true": true, "false": true, "null": true, "var": true, "record": true, "yield": true,
+}
+
+// javaFieldName turns a device name such as "left_front" or "Webcam 1" into a camelCase identifier.
+func javaFieldName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for i, w := range words {
+		// Keep each word as typed apart from the case of its first letter (leftFront, imu, webcam1).
+		first, size := utf8.DecodeRuneInString(w)
+		if i == 0 {
+			first = unicode.ToLower(first)
+		} else {
+			first = unicode.ToUpper(first)
+		}
+		b.WriteRune(first)
+		b.WriteString(w[size:])
+	}
+	field := b.String()
+	if field == "" {
+		field = "device"
+	}
+	if first, _ := utf8.DecodeRuneInString(field); unicode.IsDigit(first) {
+		field = "device" + field
+	}
+	if javaKeywords[field] {
+		field += "Device"
+	}
+	return field
+}
+
+// javaString quotes s as a Java string literal. Control characters use octal escapes: \u escapes
+// are translated before Java parses the source, so \u000a would end the string.
+func javaString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+type codegenField struct {
+	Name   string // Java field name
+	Device string // configured device name, as a Java string literal
+	Class  string
+	Module string
+	Port   string
+}
+
+type codegenData struct {
+	Marker  string
+	Header  string
+	Config  string
+	Class   string
+	Imports []string
+	Fields  []codegenField
+}
+
+var robotHardwareTemplate = template.Must(template.New("hardware").Parse(`{{.Marker}}
+// Source: hardware configuration "{{.Config}}". Regenerate with 'ftc-helper hwconfig codegen' after changing it.
+
+{{.Header}}package org.firstinspires.ftc.teamcode;
+
+{{range .Imports}}import {{.}};
+{{end}}
+/**
+ * Devices in the "{{.Config}}" robot configuration.
+ */
+public class {{.Class}} {
+{{range .Fields}}    /** {{.Module}}{{if .Port}}, {{.Port}}{{end}} */
+    public {{.Class}} {{.Name}};
+{{end}}
+    /** Looks up every device in the hardware map. Call from init() or runOpMode(). */
+    public void init(HardwareMap hardwareMap) {
+{{range .Fields}}        {{.Name}} = hardwareMap.get({{.Class}}.class, {{.Device}});
+{{end}}    }
+}
+`))
+
+// generateRobotHardware renders a Java class with a field for every device in cfg.
+func generateRobotHardware(cfg *HardwareConfig, className string, p TeamProfile) (string, error) {
+	data := codegenData{
+		Marker: generatedMarker,
+		Header: sourceHeader(className+".java", p),
+		Config: cfg.Name,
+		Class:  className,
+	}
+
+	imports := map[string]bool{"com.qualcomm.robotcore.hardware.HardwareMap": true}
+	used := map[string]bool{}
+	for _, d := range cfg.Devices {
+		t := javaTypeFor(d)
+		imports[t.Import] = true
+
+		// Devices can differ only in case or punctuation, or already end in a number ("arm", "arm2"),
+		// so count up until the name is free.
+		base := javaFieldName(d.Name)
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s%d", base, n)
+		}
+		used[name] = true
+		var port string
+		if d.Port != "" {
+			port = "port " + d.Port
+		}
+		if d.Bus != "" {
+			port = "I2C bus " + d.Bus
+		}
+		data.Fields = append(data.Fields, codegenField{
+			Name:   name,
+			Device: javaString(d.Name),
+			Class:  t.Class,
+			Module: d.Module,
+			Port:   port,
+		})
+	}
+	for imp := range imports {
+		data.Imports = append(data.Imports, imp)
+	}
+	sort.Strings(data.Imports)
+
+	var buf bytes.Buffer
+	if err := robotHardwareTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// writeGeneratedFile writes content to p unless p exists without the generated marker. It reports
+// whether the file changed.
+func writeGeneratedFile(p, content string) (bool, error) {
+	old, err := os.ReadFile(p)
+	if err == nil {
+		if !strings.HasPrefix(string(old), generatedMarker) {
+			return false, fmt.Errorf("%s exists and was not generated by ftc-helper; rename it or choose another --class", p)
+		}
+		if string(old) == content {
+			return false, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+	return true, os.WriteFile(p, []byte(content), 0644)
+}
+
+// loadHardwareConfigArg reads a configuration given as a file path or as the name of one stored in the project.
+func loadHardwareConfigArg(projectPath, arg string) (*HardwareConfig, error) {
+	p := arg
+	if _, err := os.Stat(p); err != nil {
+		p = filepath.Join(projectPath, hwconfigDir, strings.TrimSuffix(arg, ".xml")+".xml")
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("configuration %s not found as a file or in %s", arg, filepath.Join(projectPath, hwconfigDir))
+	}
+	return parseHardwareConfig(strings.TrimSuffix(filepath.Base(p), ".xml"), b)
+}
+
+var hwconfigCodegenCmd = &cobra.Command{
+	Use:   "codegen [project_name] [config.xml]",
+	Short: "Generate a RobotHardware class with a typed field for every configured device",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		className, _ := cmd.Flags().GetString("class")
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if !javaIdentifierRe.MatchString(className) {
+			fmt.Printf("Invalid class name '%s'.\n", className)
+			return
+		}
+
+		var cfg *HardwareConfig
+		if len(args) == 2 {
+			cfg, err = loadHardwareConfigArg(projectPath, args[1])
+		} else {
+			cfg, err = activeHardwareConfig(projectPath, "")
+		}
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		src, err := generateRobotHardware(cfg, className, teamProfile())
+		if err != nil {
+			fmt.Println("Error generating code:", err)
+			return
+		}
+		out := filepath.Join(teamCodeDir(args[0]), className+".java")
+		changed, err := writeGeneratedFile(out, src)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		if !changed {
+			fmt.Printf("%s is up to date.\n", out)
+			return
+		}
+		fmt.Printf("Generated %s with %d devices from %s\n", out, len(cfg.Devices), cfg.Name)
+	},
+}
+
+func init() {
+	hwconfigCodegenCmd.Flags().String("class", "RobotHardware", "Name of the generated class")
+	hwconfigCmd.AddCommand(hwconfigCodegenCmd)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJavaFieldName(t *testing.T) {
+	tests := map[string]string{
+		"left_front":  "leftFront",
+		"Webcam 1":    "webcam1",
+		"imu":         "imu",
+		"armMotor":    "armMotor",
+		"1st-stage":   "device1stStage",
+		"class":       "classDevice",
+		"  ":          "device",
+		"Intake Left": "intakeLeft",
+		"Ärm links":   "ärmLinks",
+		"ñ_über":      "ñÜber",
+	}
+	for in, want := range tests {
+		if got := javaFieldName(in); got != want {
+			t.Errorf("javaFieldName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateRobotHardware(t *testing.T) {
+	cfg, err := parseHardwareConfig("competition", []byte(testRobotConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generateRobotHardware(cfg, "RobotHardware", TeamProfile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(src, generatedMarker+"\n") {
+		t.Errorf("missing marker:\n%s", src)
+	}
+	for _, want := range []string{
+		"package org.firstinspires.ftc.teamcode;",
+		"import com.qualcomm.robotcore.hardware.DcMotorEx;",
+		"import com.qualcomm.robotcore.hardware.HardwareMap;",
+		"import org.firstinspires.ftc.robotcore.external.hardware.camera.WebcamName;",
+		"public class RobotHardware {",
+		"    public DcMotorEx leftFront;",
+		"    public CRServo intake;",
+		"    public IMU imu;",
+		"    public WebcamName webcam1;",
+		"    /** Control Hub, I2C bus 1 */\n    public RevColorSensorV3 color;",
+		`        leftFront = hardwareMap.get(DcMotorEx.class, "left_front");`,
+		`        webcam1 = hardwareMap.get(WebcamName.class, "Webcam 1");`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code missing %q:\n%s", want, src)
+		}
+	}
+
+	// Everything generated must pass validate against the same configuration.
+	dir := t.TempDir()
+	root := makeTestProject(t, dir, "robot")
+	os.WriteFile(filepath.Join(root, "TeamCode", "src", "main", "java", "org", "firstinspires", "ftc", "teamcode", "RobotHardware.java"), []byte(src), 0644)
+	uses, _ := findHardwareMapUses(root)
+	if len(uses) != len(cfg.Devices) {
+		t.Errorf("found %d lookups, want %d", len(uses), len(cfg.Devices))
+	}
+	if problems := validateHardwareMap(cfg, uses); len(problems) != 0 {
+		t.Errorf("generated code fails validate: %v", problems)
+	}
+}
+
+func TestJavaString(t *testing.T) {
+	tests := map[string]string{
+		"left_front": `"left_front"`,
+		`say "hi"`:   `"say \"hi\""`,
+		`C:\arm`:     `"C:\\arm"`,
+		"a\nb":       `"a\012b"`,
+		"Ärm":        `"Ärm"`,
+	}
+	for in, want := range tests {
+		if got := javaString(in); got != want {
+			t.Errorf("javaString(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestGenerateRobotHardware_UniqueFields(t *testing.T) {
+	cfg := &HardwareConfig{Name: "test", Devices: []HardwareDevice{
+		{Name: "Arm", Type: "goBILDA5202SeriesMotor"},
+		{Name: "arm", Type: "goBILDA5202SeriesMotor"},
+		{Name: "arm2", Type: "goBILDA5202SeriesMotor"},
+		{Name: `claw\left`, Type: "Servo"},
+	}}
+	src, err := generateRobotHardware(cfg, "RobotHardware", TeamProfile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`arm = hardwareMap.get(DcMotorEx.class, "Arm");`,
+		`arm2 = hardwareMap.get(DcMotorEx.class, "arm");`,
+		`arm22 = hardwareMap.get(DcMotorEx.class, "arm2");`,
+		`clawLeft = hardwareMap.get(Servo.class, "claw\\left");`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code missing %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestWriteGeneratedFile(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "RobotHardware.java")
+	content := generatedMarker + "\nclass RobotHardware {}\n"
+
+	if changed, err := writeGeneratedFile(p, content); err != nil || !changed {
+		t.Fatalf("first write = %v, %v", changed, err)
+	}
+	if changed, err := writeGeneratedFile(p, content); err != nil || changed {
+		t.Errorf("rewrite of same content = %v, %v", changed, err)
+	}
+	if changed, err := writeGeneratedFile(p, content+"// more\n"); err != nil || !changed {
+		t.Errorf("regenerate = %v, %v", changed, err)
+	}
+
+	os.WriteFile(p, []byte("class RobotHardware { /* hand written */ }"), 0644)
+	if _, err := writeGeneratedFile(p, content); err == nil {
+		t.Error("expected refusal to overwrite a hand-written file")
+	}
+	if b, _ := os.ReadFile(p); !strings.Contains(string(b), "hand written") {
+		t.Error("hand-written file was overwritten")
+	}
+}
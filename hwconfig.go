@@ -0,0 +1,519 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// robotConfigDir is where the Robot Controller keeps hardware configuration files.
+const robotConfigDir = "/sdcard/FIRST"
+
+// hwconfigDir is where configurations are stored inside a project.
+const hwconfigDir = "hwconfig"
+
+// xmlNode is a generic XML element; robot configurations nest hubs and devices freely.
+type xmlNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []xmlNode  `xml:",any"`
+}
+
+func (n xmlNode) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// HardwareDevice is one named device in a robot configuration.
+type HardwareDevice struct {
+	Name   string
+	Type   string // XML element, e.g. goBILDA5202SeriesMotor
+	Port   string
+	Bus    string // I2C bus, empty for other devices
+	Module string // hub or device the device is attached to
+}
+
+// Kind groups device types for display and type checks: motor, servo, i2c, digital, analog, camera or other.
+func (d HardwareDevice) Kind() string {
+	t := strings.ToLower(d.Type)
+	switch {
+	case d.Bus != "":
+		return "i2c"
+	case strings.Contains(t, "motor"):
+		return "motor"
+	case strings.Contains(t, "servo"):
+		return "servo"
+	case strings.Contains(t, "webcam") || strings.Contains(t, "camera"):
+		return "camera"
+	case strings.Contains(t, "digital") || strings.Contains(t, "touch") || strings.Contains(t, "led"):
+		return "digital"
+	case strings.Contains(t, "analog") || strings.Contains(t, "potentiometer"):
+		return "analog"
+	}
+	return "other"
+}
+
+// HardwareConfig is a parsed robot configuration file.
+type HardwareConfig struct {
+	Name    string
+	Devices []HardwareDevice
+}
+
+// containerElements hold devices rather than being devices themselves.
+var containerElements = map[string]bool{
+	"Robot":                   true,
+	"LynxUsbDevice":           true,
+	"LynxModule":              true,
+	"ModernRoboticsUsbDevice": true,
+	"EthernetDevice":          true,
+}
+
+// parseHardwareConfig reads a Robot Controller configuration XML file.
+func parseHardwareConfig(name string, data []byte) (*HardwareConfig, error) {
+	var root xmlNode
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if root.XMLName.Local != "Robot" {
+		return nil, fmt.Errorf("%s is not a robot configuration (root element %s)", name, root.XMLName.Local)
+	}
+
+	cfg := &HardwareConfig{Name: name}
+	var walk func(n xmlNode, module string)
+	walk = func(n xmlNode, module string) {
+		for _, c := range n.Children {
+			if containerElements[c.XMLName.Local] {
+				walk(c, valueOr(c.attr("name"), c.XMLName.Local))
+				continue
+			}
+			if c.attr("name") == "" {
+				continue
+			}
+			cfg.Devices = append(cfg.Devices, HardwareDevice{
+				Name:   c.attr("name"),
+				Type:   c.XMLName.Local,
+				Port:   c.attr("port"),
+				Bus:    c.attr("bus"),
+				Module: module,
+			})
+			// Some devices (e.g. Servo Hubs) have devices of their own.
+			if len(c.Children) > 0 {
+				walk(c, c.attr("name"))
+			}
+		}
+	}
+	walk(root, "Robot")
+	return cfg, nil
+}
+
+// device returns the device with the given name.
+func (c *HardwareConfig) device(name string) (HardwareDevice, bool) {
+	for _, d := range c.Devices {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return HardwareDevice{}, false
+}
+
+// print writes the devices grouped by hub, ordered by kind and port.
+func (c *HardwareConfig) print() {
+	fmt.Printf("Configuration: %s\n", c.Name)
+	var modules []string
+	byModule := map[string][]HardwareDevice{}
+	for _, d := range c.Devices {
+		if _, ok := byModule[d.Module]; !ok {
+			modules = append(modules, d.Module)
+		}
+		byModule[d.Module] = append(byModule[d.Module], d)
+	}
+	for _, m := range modules {
+		devices := byModule[m]
+		sort.SliceStable(devices, func(i, j int) bool {
+			if devices[i].Kind() != devices[j].Kind() {
+				return devices[i].Kind() < devices[j].Kind()
+			}
+			return devices[i].Bus+devices[i].Port < devices[j].Bus+devices[j].Port
+		})
+		fmt.Printf("\n%s\n", m)
+		for _, d := range devices {
+			port := d.Port
+			if d.Bus != "" {
+				port = "bus " + d.Bus
+			}
+			fmt.Printf("  %-8s %-8s %-24s %s\n", d.Kind(), port, d.Name, d.Type)
+		}
+	}
+}
+
+// HardwareMapUse is a device name looked up from the hardware map in TeamCode.
+type HardwareMapUse struct {
+	Name  string
+	Class string // requested class, e.g. DcMotorEx; empty when unknown
+	File  string
+	Line  int
+}
+
+// hardwareMapGet matches hardwareMap.get(DcMotor.class, "name"), hardwareMap.get(DcMotor::class.java, "name"),
+// hardwareMap.dcMotor.get("name") and tryGet variants. Names that aren't string literals can't be checked.
+var hardwareMapGet = regexp.MustCompile(`hardwareMap\s*\.\s*(?:(\w+)\s*\.\s*)?(?:get|tryGet)\s*\(\s*(?:([\w.]+?)(?:\.class|::class\.java)\s*,\s*)?"([^"]+)"`)
+
+// deviceMappingClasses maps the hardwareMap.<mapping> fields to the class they return.
+var deviceMappingClasses = map[string]string{
+	"dcMotor":           "DcMotor",
+	"servo":             "Servo",
+	"crservo":           "CRServo",
+	"digitalChannel":    "DigitalChannel",
+	"analogInput":       "AnalogInput",
+	"colorSensor":       "ColorSensor",
+	"touchSensor":       "TouchSensor",
+	"distanceSensor":    "DistanceSensor",
+	"voltageSensor":     "VoltageSensor",
+	"led":               "LED",
+	"dcMotorController": "DcMotorController",
+	"servoController":   "ServoController",
+}
+
+// findHardwareMapUses scans TeamCode sources for device names looked up in the hardware map.
+func findHardwareMapUses(projectPath string) ([]HardwareMapUse, error) {
+	var uses []HardwareMapUse
+	src := filepath.Join(projectPath, "TeamCode", "src")
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".java") && !strings.HasSuffix(p, ".kt") {
+			return nil
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(projectPath, p)
+		for i, line := range strings.Split(string(b), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "//") {
+				continue
+			}
+			for _, m := range hardwareMapGet.FindAllStringSubmatch(line, -1) {
+				class := m[2]
+				if class == "" {
+					class = deviceMappingClasses[m[1]]
+				}
+				if j := strings.LastIndex(class, "."); j >= 0 {
+					class = class[j+1:]
+				}
+				uses = append(uses, HardwareMapUse{Name: m[3], Class: class, File: filepath.ToSlash(rel), Line: i + 1})
+			}
+		}
+		return nil
+	})
+	return uses, err
+}
+
+// classKinds maps SDK classes to the device kind they need, for catching e.g. a servo read as a motor.
+var classKinds = map[string]string{
+	"DcMotor":       "motor",
+	"DcMotorEx":     "motor",
+	"DcMotorSimple": "motor",
+	"Servo":         "servo",
+	"ServoImplEx":   "servo",
+	"CRServo":       "servo",
+	"WebcamName":    "camera",
+}
+
+// validateHardwareMap returns a problem for each use whose name is missing from the configuration
+// or whose class doesn't fit the configured device.
+func validateHardwareMap(cfg *HardwareConfig, uses []HardwareMapUse) []string {
+	var problems []string
+	for _, u := range uses {
+		where := fmt.Sprintf("%s:%d", u.File, u.Line)
+		d, ok := cfg.device(u.Name)
+		if !ok {
+			msg := fmt.Sprintf("%s: %q is not in configuration %s", where, u.Name, cfg.Name)
+			for _, other := range cfg.Devices {
+				if strings.EqualFold(strings.TrimSpace(other.Name), strings.TrimSpace(u.Name)) {
+					msg += fmt.Sprintf(" (did you mean %q? names are case and space sensitive)", other.Name)
+					break
+				}
+			}
+			problems = append(problems, msg)
+			continue
+		}
+		if want, ok := classKinds[u.Class]; ok && d.Kind() != "other" && d.Kind() != want {
+			problems = append(problems, fmt.Sprintf("%s: %q is requested as %s but configured as %s (%s)", where, u.Name, u.Class, d.Kind(), d.Type))
+		}
+	}
+	return problems
+}
+
+// projectHardwareConfigs lists the configuration names stored in a project.
+func projectHardwareConfigs(projectPath string) []string {
+	matches, _ := filepath.Glob(filepath.Join(projectPath, hwconfigDir, "*.xml"))
+	var names []string
+	for _, m := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(m), ".xml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// activeHardwareConfig picks the configuration to use: the one named, the one recorded in the project
+// manifest, or the only one in the project.
+func activeHardwareConfig(projectPath, name string) (*HardwareConfig, error) {
+	if name == "" {
+		if m, err := readManifest(projectPath); err == nil && m.HardwareConfig != "" {
+			name = m.HardwareConfig
+		}
+	}
+	if name == "" {
+		names := projectHardwareConfigs(projectPath)
+		switch len(names) {
+		case 0:
+			return nil, fmt.Errorf("no hardware configurations in the project; run 'ftc-helper hwconfig pull' first")
+		case 1:
+			name = names[0]
+		default:
+			return nil, fmt.Errorf("several configurations (%s); choose one with --config", strings.Join(names, ", "))
+		}
+	}
+	b, err := os.ReadFile(filepath.Join(projectPath, hwconfigDir, strings.TrimSuffix(name, ".xml")+".xml"))
+	if err != nil {
+		return nil, err
+	}
+	return parseHardwareConfig(strings.TrimSuffix(name, ".xml"), b)
+}
+
+// setActiveHardwareConfig records the active configuration in the project manifest, creating one if needed.
+func setActiveHardwareConfig(projectPath, name string) error {
+	m, err := readManifest(projectPath)
+	if err != nil {
+		m = &ProjectManifest{Name: filepath.Base(projectPath)}
+		m.SDKVersion, _, _ = detectSDKVersion(projectPath)
+	}
+	m.HardwareConfig = name
+	return writeManifest(projectPath, m)
+}
+
+// pullHardwareConfigs copies every robot configuration from the Robot Controller into the project.
+func pullHardwareConfigs(adb ADB, projectPath string) ([]string, error) {
+	// One name per line: configuration names may contain spaces.
+	out, err := adb.Run("shell", "ls", "-1", robotConfigDir)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(projectPath, hwconfigDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var pulled []string
+	for _, f := range strings.Split(string(out), "\n") {
+		f = strings.TrimRight(f, "\r")
+		if !strings.HasSuffix(f, ".xml") {
+			continue
+		}
+		// Pull next to the final name and only replace the project's copy once the file parses.
+		tmp := filepath.Join(dir, "."+f+".part")
+		if _, err := adb.Run("pull", path.Join(robotConfigDir, f), tmp); err != nil {
+			os.Remove(tmp)
+			return pulled, err
+		}
+		b, err := os.ReadFile(tmp)
+		if err != nil {
+			os.Remove(tmp)
+			return pulled, err
+		}
+		// /sdcard/FIRST also holds other XML files; keep only robot configurations.
+		if _, err := parseHardwareConfig(f, b); err != nil {
+			os.Remove(tmp)
+			continue
+		}
+		if err := os.Rename(tmp, filepath.Join(dir, f)); err != nil {
+			os.Remove(tmp)
+			return pulled, err
+		}
+		pulled = append(pulled, strings.TrimSuffix(f, ".xml"))
+	}
+	return pulled, nil
+}
+
+// hwconfig: robot hardware configuration files
+var hwconfigCmd = &cobra.Command{
+	Use:   "hwconfig",
+	Short: "Manage robot hardware configurations",
+}
+
+var hwconfigPullCmd = &cobra.Command{
+	Use:   "pull [project_name]",
+	Short: "Copy hardware configurations from the Robot Controller into the project",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		active, _ := cmd.Flags().GetString("active")
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		robotName, _ := cmd.Flags().GetString("robot")
+		_, _, adb, err := robotDevice(robotName)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		pulled, err := pullHardwareConfigs(adb, projectPath)
+		if err != nil {
+			fmt.Println("Error pulling configurations:", err)
+			return
+		}
+		if len(pulled) == 0 {
+			fmt.Println("No hardware configurations found in", robotConfigDir)
+			return
+		}
+		for _, name := range pulled {
+			fmt.Printf("Pulled %s\n", name)
+		}
+
+		if active == "" && len(pulled) == 1 {
+			active = pulled[0]
+		}
+		if active != "" {
+			if err := setActiveHardwareConfig(projectPath, strings.TrimSuffix(active, ".xml")); err != nil {
+				fmt.Println("Error recording the active configuration:", err)
+				return
+			}
+			fmt.Println("Active configuration:", active)
+		}
+	},
+}
+
+var hwconfigPushCmd = &cobra.Command{
+	Use:   "push [project_name]",
+	Short: "Copy the project's hardware configurations to the Robot Controller",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		only, _ := cmd.Flags().GetString("config")
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		names := projectHardwareConfigs(projectPath)
+		if only != "" {
+			names = []string{strings.TrimSuffix(only, ".xml")}
+		}
+		if len(names) == 0 {
+			fmt.Println("No hardware configurations in the project.")
+			return
+		}
+
+		robotName, _ := cmd.Flags().GetString("robot")
+		_, _, adb, err := robotDevice(robotName)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		for _, name := range names {
+			local := filepath.Join(projectPath, hwconfigDir, name+".xml")
+			b, err := os.ReadFile(local)
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if _, err := parseHardwareConfig(name, b); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if _, err := adb.Run("push", local, path.Join(robotConfigDir, name+".xml")); err != nil {
+				fmt.Println("Error pushing configuration:", err)
+				return
+			}
+			fmt.Printf("Pushed %s\n", name)
+		}
+		fmt.Println("Activate the configuration on the Driver Station (Configure Robot) to use it.")
+	},
+}
+
+var hwconfigShowCmd = &cobra.Command{
+	Use:   "show [project_name]",
+	Short: "List the devices in a hardware configuration by hub and port",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("config")
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		cfg, err := activeHardwareConfig(projectPath, name)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		cfg.print()
+	},
+}
+
+var hwconfigValidateCmd = &cobra.Command{
+	Use:   "validate [project_name]",
+	Short: "Check that every hardwareMap device name in TeamCode exists in the configuration",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("config")
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		cfg, err := activeHardwareConfig(projectPath, name)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		uses, err := findHardwareMapUses(projectPath)
+		if err != nil {
+			fmt.Println("Error reading TeamCode:", err)
+			return
+		}
+
+		problems := validateHardwareMap(cfg, uses)
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		if len(problems) > 0 {
+			fmt.Printf("%d problem(s) in %d hardwareMap lookups against configuration %s.\n", len(problems), len(uses), cfg.Name)
+			os.Exit(1)
+		}
+		fmt.Printf("All %d hardwareMap lookups match configuration %s.\n", len(uses), cfg.Name)
+	},
+}
+
+func init() {
+	hwconfigPullCmd.Flags().String("active", "", "Configuration to record as active for show and validate")
+	hwconfigPushCmd.Flags().String("config", "", "Only push this configuration")
+	for _, c := range []*cobra.Command{hwconfigPullCmd, hwconfigPushCmd} {
+		c.Flags().String("robot", "", "Saved robot to use (default: the active robot)")
+	}
+	hwconfigShowCmd.Flags().String("config", "", "Configuration to show (default: the active one)")
+	hwconfigValidateCmd.Flags().String("config", "", "Configuration to check against (default: the active one)")
+	hwconfigCmd.AddCommand(hwconfigPullCmd, hwconfigPushCmd, hwconfigShowCmd, hwconfigValidateCmd)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const testRobotConfig = `<?xml version='1.0' encoding='UTF-8' standalone='yes' ?>
+<Robot type="FirstInspires-FTC">
+    <LynxUsbDevice name="Control Hub Portal" serialNumber="(embedded)" parentModuleAddress="173">
+        <LynxModule name="Expansion Hub 2" port="2">
+            <goBILDA5202SeriesMotor name="arm" port="0" />
+        </LynxModule>
+        <LynxModule name="Control Hub" port="173">
+            <goBILDA5202SeriesMotor name="left_front" port="0" />
+            <goBILDA5202SeriesMotor name="right_front" port="1" />
+            <Servo name="claw" port="0" />
+            <ContinuousRotationServo name="intake" port="1" />
+            <ControlHubImuBHI260AP name="imu" port="0" bus="0" />
+            <RevColorSensorV3 name="color" port="0" bus="1" />
+            <DigitalDevice name="touch" port="1" />
+        </LynxModule>
+    </LynxUsbDevice>
+    <Webcam name="Webcam 1" serialNumber="ABC123" />
+</Robot>
+`
+
+func TestParseHardwareConfig(t *testing.T) {
+	cfg, err := parseHardwareConfig("robot", []byte(testRobotConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Devices) != 9 {
+		t.Fatalf("got %d devices: %+v", len(cfg.Devices), cfg.Devices)
+	}
+	tests := map[string]struct{ kind, module string }{
+		"arm":      {"motor", "Expansion Hub 2"},
+		"claw":     {"servo", "Control Hub"},
+		"intake":   {"servo", "Control Hub"},
+		"imu":      {"i2c", "Control Hub"},
+		"touch":    {"digital", "Control Hub"},
+		"Webcam 1": {"camera", "Robot"},
+	}
+	for name, want := range tests {
+		d, ok := cfg.device(name)
+		if !ok || d.Kind() != want.kind || d.Module != want.module {
+			t.Errorf("device %q = %+v (kind %s), want %+v", name, d, d.Kind(), want)
+		}
+	}
+
+	if _, err := parseHardwareConfig("prefs", []byte(`<map><string name="x">y</string></map>`)); err == nil {
+		t.Error("expected error for non-robot XML")
+	}
+}
+
+func TestFindHardwareMapUses(t *testing.T) {
+	root := makeTestProject(t, t.TempDir(), "robot")
+	src := `package org.firstinspires.ftc.teamcode;
+public class Drive extends LinearOpMode {
+    public void runOpMode() {
+        DcMotorEx lf = hardwareMap.get(DcMotorEx.class, "left_front");
+        Servo claw = hardwareMap.servo.get("claw");
+        // DcMotor old = hardwareMap.get(DcMotor.class, "old_motor");
+        IMU imu = hardwareMap.get(IMU.class, name);
+    }
+}`
+	kt := `class Arm(hw: HardwareMap) { val arm = hardwareMap.get(com.qualcomm.robotcore.hardware.DcMotor::class.java, "arm") }`
+	dir := filepath.Join(root, "TeamCode", "src", "main", "java", "org", "firstinspires", "ftc", "teamcode")
+	os.WriteFile(filepath.Join(dir, "Drive.java"), []byte(src), 0644)
+	os.WriteFile(filepath.Join(dir, "Arm.kt"), []byte(kt), 0644)
+
+	uses, err := findHardwareMapUses(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, u := range uses {
+		got = append(got, u.Name+":"+u.Class)
+	}
+	want := "arm:DcMotor,left_front:DcMotorEx,claw:Servo"
+	if strings.Join(got, ",") != want {
+		t.Errorf("uses = %v, want %s", got, want)
+	}
+	if uses[1].Line != 4 || !strings.HasSuffix(uses[1].File, "teamcode/Drive.java") {
+		t.Errorf("location = %s:%d", uses[1].File, uses[1].Line)
+	}
+}
+
+func TestValidateHardwareMap(t *testing.T) {
+	cfg, _ := parseHardwareConfig("robot", []byte(testRobotConfig))
+	uses := []HardwareMapUse{
+		{Name: "left_front", Class: "DcMotorEx", File: "Drive.java", Line: 1},
+		{Name: "Left_Front", Class: "DcMotor", File: "Drive.java", Line: 2},
+		{Name: "lift", Class: "DcMotor", File: "Drive.java", Line: 3},
+		{Name: "claw", Class: "DcMotor", File: "Drive.java", Line: 4},
+		{Name: "color", Class: "ColorSensor", File: "Drive.java", Line: 5},
+	}
+	problems := validateHardwareMap(cfg, uses)
+	if len(problems) != 3 {
+		t.Fatalf("problems = %v", problems)
+	}
+	for i, want := range []string{`Drive.java:2: "Left_Front" is not in configuration robot (did you mean "left_front"`, `Drive.java:3: "lift"`, `Drive.java:4: "claw" is requested as DcMotor but configured as servo`} {
+		if !strings.HasPrefix(problems[i], want) {
+			t.Errorf("problem %d = %q, want prefix %q", i, problems[i], want)
+		}
+	}
+}
+
+func TestPullHardwareConfigs(t *testing.T) {
+	root := makeTestProject(t, t.TempDir(), "robot")
+	adb := &fakeADB{files: map[string]string{
+		"/sdcard/FIRST/competition.xml": testRobotConfig,
+		"/sdcard/FIRST/Comp Bot.xml":    testRobotConfig,
+		"/sdcard/FIRST/prefs.xml":       "<map/>",
+		"/sdcard/FIRST/matchlog.txt":    "log",
+	}}
+	// A project file with the same name as an XML file that isn't a configuration must survive the pull.
+	prefs := filepath.Join(root, hwconfigDir, "prefs.xml")
+	if err := os.MkdirAll(filepath.Dir(prefs), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(prefs, []byte(testRobotConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pulled, err := pullHardwareConfigs(adb, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(pulled)
+	if strings.Join(pulled, ",") != "Comp Bot,competition" {
+		t.Errorf("pulled = %v", pulled)
+	}
+	if b, err := os.ReadFile(prefs); err != nil || string(b) != testRobotConfig {
+		t.Errorf("existing prefs.xml was replaced: %q, %v", b, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(prefs))
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".part") {
+			t.Errorf("temporary file left behind: %s", e.Name())
+		}
+	}
+
+	if err := setActiveHardwareConfig(root, "competition"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := activeHardwareConfig(root, "")
+	if err != nil || cfg.Name != "competition" {
+		t.Errorf("activeHardwareConfig = %v, %v", cfg, err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// javaHome locates a JDK: JAVA_HOME, then the runtime bundled with Android Studio (which every team
+// has installed), then java on PATH.
+func javaHome() (string, error) {
+	exe := "java"
+	if runtime.GOOS == "windows" {
+		exe = "java.exe"
+	}
+	hasJava := func(home string) bool {
+		_, err := os.Stat(filepath.Join(home, "bin", exe))
+		return home != "" && err == nil
+	}
+
+	if home := os.Getenv("JAVA_HOME"); hasJava(home) {
+		return home, nil
+	}
+
+	var candidates []string
+	if studio, err := findAndroidStudioExe(); err == nil {
+		root := filepath.Dir(filepath.Dir(studio))
+		candidates = append(candidates, filepath.Join(root, "jbr"), filepath.Join(root, "jre"))
+	}
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = append(candidates, "/Applications/Android Studio.app/Contents/jbr/Contents/Home")
+	case "linux":
+		candidates = append(candidates, "/opt/android-studio/jbr", filepath.Join(home, "android-studio", "jbr"))
+	}
+	for _, c := range candidates {
+		if hasJava(c) {
+			return c, nil
+		}
+	}
+
+	if p, err := exec.LookPath(exe); err == nil {
+		if resolved, err := filepath.EvalSymlinks(p); err == nil {
+			return filepath.Dir(filepath.Dir(resolved)), nil
+		}
+	}
+	return "", fmt.Errorf("no Java found; install Android Studio or set JAVA_HOME")
+}
+
+// findJava returns the java executable of javaHome.
+func findJava() (string, error) {
+	home, err := javaHome()
+	if err != nil {
+		return "", err
+	}
+	exe := "java"
+	if runtime.GOOS == "windows" {
+		exe = "java.exe"
+	}
+	return filepath.Join(home, "bin", exe), nil
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"sigs.k8s.io/yaml"
+)
+
+// Library is a community library that can be added to a project's Gradle build.
+type Library struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Version is the default version, substituted for {version} in Dependencies.
+	Version      string   `json:"version"`
+	Repositories []string `json:"repositories"`
+	Dependencies []string `json:"dependencies"`
+}
+
+// builtinLibraries maps library names to their Maven coordinates and required repositories.
+var builtinLibraries = map[string]Library{
+	"roadrunner": {
+		Name:         "roadrunner",
+		Description:  "Road Runner motion planning (1.0)",
+		Version:      "1.0.1",
+		Repositories: []string{"https://maven.brott.dev/"},
+		Dependencies: []string{
+			"com.acmerobotics.roadrunner:ftc:0.1.21",
+			"com.acmerobotics.roadrunner:core:{version}",
+			"com.acmerobotics.roadrunner:actions:{version}",
+			"com.acmerobotics.dashboard:dashboard:0.4.17",
+		},
+	},
+	"dashboard": {
+		Name:         "dashboard",
+		Description:  "FTC Dashboard web telemetry and tuning",
+		Version:      "0.4.17",
+		Repositories: []string{"https://maven.brott.dev/"},
+		Dependencies: []string{"com.acmerobotics.dashboard:dashboard:{version}"},
+	},
+	"ftclib": {
+		Name:         "ftclib",
+		Description:  "FTCLib command-based framework and utilities",
+		Version:      "2.1.1",
+		Dependencies: []string{"org.ftclib.ftclib:core:{version}"},
+	},
+	"pedropathing": {
+		Name:         "pedropathing",
+		Description:  "Pedro Pathing follower",
+		Version:      "2.0.0",
+		Repositories: []string{"https://mymaven.bylazar.com/releases"},
+		Dependencies: []string{"com.pedropathing:ftc:{version}"},
+	},
+}
+
+// librariesFile returns the path of the user's library catalog (libraries_file).
+func librariesFile() string {
+	if p := viper.GetString("libraries_file"); p != "" {
+		return p
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".ftc-helper-libraries.yaml")
+}
+
+// loadUserLibraries reads extra catalog entries from a YAML list of libraries.
+// A missing file is not an error.
+func loadUserLibraries(p string) ([]Library, error) {
+	b, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var libs []Library
+	if err := yaml.Unmarshal(b, &libs); err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	for i, l := range libs {
+		if l.Name == "" || len(l.Dependencies) == 0 {
+			return nil, fmt.Errorf("%s: entry %d needs a name and at least one dependency", p, i+1)
+		}
+		libs[i].Name = strings.ToLower(l.Name)
+	}
+	return libs, nil
+}
+
+// libraryCatalog returns the built-in libraries plus those from the user's catalog file,
+// sorted by name. User entries replace built-in ones with the same name.
+func libraryCatalog() ([]Library, error) {
+	byName := map[string]Library{}
+	for name, l := range builtinLibraries {
+		byName[name] = l
+	}
+	user, err := loadUserLibraries(librariesFile())
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range user {
+		byName[l.Name] = l
+	}
+
+	libs := make([]Library, 0, len(byName))
+	for _, l := range byName {
+		libs = append(libs, l)
+	}
+	sort.Slice(libs, func(i, j int) bool { return libs[i].Name < libs[j].Name })
+	return libs, nil
+}
+
+// lookupLibrary resolves "name" or "name@version" against the catalog.
+func lookupLibrary(spec string) (Library, error) {
+	name, version, _ := strings.Cut(strings.TrimSpace(spec), "@")
+	name = strings.ToLower(name)
+	catalog, err := libraryCatalog()
+	if err != nil {
+		return Library{}, err
+	}
+	for _, l := range catalog {
+		if l.Name == name {
+			if version != "" {
+				l.Version = version
+			}
+			return l, nil
+		}
+	}
+	return Library{}, fmt.Errorf("unknown library %q", name)
+}
+
+// coordinates returns the library's dependencies with the version substituted.
+func (l Library) coordinates() []string {
+	deps := make([]string, len(l.Dependencies))
+	for i, d := range l.Dependencies {
+		deps[i] = strings.ReplaceAll(d, "{version}", l.Version)
+	}
+	return deps
+}
+
+// lib: manage community libraries in a project's Gradle build
+var libCmd = &cobra.Command{
+	Use:   "lib",
+	Short: "Add, remove and list community libraries",
+}
+
+var libListCmd = &cobra.Command{
+	Use:   "list [project_name]",
+	Short: "List available libraries, marking those installed in a project",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog, err := libraryCatalog()
+		if err != nil {
+			fmt.Println("Error loading library catalog:", err)
+			return
+		}
+
+		installed := map[string]bool{}
+		if len(args) == 1 {
+			names, err := projectLibraries(filepath.Join(workDir, args[0]))
+			if err != nil {
+				fmt.Println("Error reading project:", err)
+				return
+			}
+			for _, n := range names {
+				installed[n] = true
+			}
+		}
+
+		fmt.Println("Available libraries:")
+		for _, l := range catalog {
+			mark := " "
+			if installed[l.Name] {
+				mark = "*"
+			}
+			fmt.Printf("%s %-14s %-10s %s\n", mark, l.Name, l.Version, l.Description)
+		}
+		if len(args) == 1 {
+			fmt.Println("* installed in", args[0])
+		}
+	},
+}
+
+var libAddCmd = &cobra.Command{
+	Use:   "add [project_name] [library[@version]]",
+	Short: "Add a library to a project",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath := filepath.Join(workDir, args[0])
+		lib, err := lookupLibrary(args[1])
+		if err != nil {
+			fmt.Println("Error:", err)
+			fmt.Println("Run 'ftc-helper lib list' to see available libraries.")
+			return
+		}
+
+		if err := addLibraryToProject(projectPath, lib); err != nil {
+			fmt.Println("Error adding library:", err)
+			return
+		}
+		updateManifestLibraries(projectPath)
+		fmt.Printf("Added %s %s to %s. Sync Gradle in Android Studio to download it.\n", lib.Name, lib.Version, args[0])
+	},
+}
+
+var libRemoveCmd = &cobra.Command{
+	Use:   "remove [project_name] [library]",
+	Short: "Remove a library from a project",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath := filepath.Join(workDir, args[0])
+		name := strings.ToLower(args[1])
+
+		installed, err := projectLibraries(projectPath)
+		if err != nil {
+			fmt.Println("Error reading project:", err)
+			return
+		}
+		found := false
+		for _, n := range installed {
+			if n == name {
+				found = true
+			}
+		}
+		if !found {
+			fmt.Printf("%s was not added by ftc-helper to %s.\n", name, args[0])
+			return
+		}
+
+		if err := removeLibraryFromProject(projectPath, name); err != nil {
+			fmt.Println("Error removing library:", err)
+			return
+		}
+		updateManifestLibraries(projectPath)
+		fmt.Printf("Removed %s from %s.\n", name, args[0])
+	},
+}
+
+// updateManifestLibraries syncs the manifest's library list with the Gradle file, if the project has a manifest.
+func updateManifestLibraries(projectPath string) {
+	m, err := readManifest(projectPath)
+	if err != nil {
+		return
+	}
+	libs, err := projectLibraries(projectPath)
+	if err != nil {
+		return
+	}
+	m.Libraries = libs
+	if err := writeManifest(projectPath, m); err != nil {
+		fmt.Println("Error updating project manifest:", err)
+	}
+}
+
+func init() {
+	libCmd.AddCommand(libListCmd)
+	libCmd.AddCommand(libAddCmd)
+	libCmd.AddCommand(libRemoveCmd)
+}
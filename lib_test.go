@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// withoutUserLibraries points libraries_file at an empty temp dir so the developer's own catalog
+// doesn't change the built-in libraries tests rely on.
+func withoutUserLibraries(t *testing.T) {
+	t.Helper()
+	viper.Set("libraries_file", filepath.Join(t.TempDir(), "libraries.yaml"))
+	t.Cleanup(func() { viper.Set("libraries_file", "") })
+}
+
+func TestLibraryCatalog_UserFile(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "libraries.yaml")
+	content := `- name: Sloth
+  description: Hot reload for TeamCode
+  version: 0.2.1
+  repositories: ["https://repo.dairy.foundation/releases"]
+  dependencies: ["dev.frozenmilk.sinister:Sloth:{version}"]
+- name: ftclib
+  description: Pinned FTCLib
+  version: 2.0.0
+  dependencies: ["org.ftclib.ftclib:core:{version}"]
+`
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("libraries_file", p)
+	defer viper.Set("libraries_file", "")
+
+	sloth, err := lookupLibrary("sloth")
+	if err != nil {
+		t.Fatalf("lookup user library: %v", err)
+	}
+	if got := sloth.coordinates(); len(got) != 1 || got[0] != "dev.frozenmilk.sinister:Sloth:0.2.1" {
+		t.Errorf("sloth coordinates = %v", got)
+	}
+
+	ftclib, err := lookupLibrary("ftclib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ftclib.Version != "2.0.0" {
+		t.Errorf("user entry should override built-in, got version %s", ftclib.Version)
+	}
+
+	if _, err := lookupLibrary("roadrunner"); err != nil {
+		t.Errorf("built-in libraries should still be available: %v", err)
+	}
+}
+
+func TestLibraryCatalog_InvalidUserFile(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "libraries.yaml")
+	os.WriteFile(p, []byte("- name: broken\n"), 0644)
+	viper.Set("libraries_file", p)
+	defer viper.Set("libraries_file", "")
+
+	if _, err := libraryCatalog(); err == nil {
+		t.Fatal("expected error for entry without dependencies")
+	}
+}
@@ -0,0 +1,571 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// LintRule is a check run by `lint`.
+type LintRule struct {
+	ID          string
+	Severity    string // error or warning
+	Description string
+}
+
+var lintRules = []LintRule{
+	{"sleep-in-iterative", "warning", "Thread.sleep in an iterative OpMode blocks the loop and can trigger the stuck-OpMode watchdog"},
+	{"missing-wait-for-start", "error", "LinearOpMode runOpMode never calls waitForStart(), so the robot moves during init"},
+	{"loop-without-active-check", "warning", "while loop in a LinearOpMode doesn't check opModeIsActive(), so Stop can't end it"},
+	{"duplicate-opmode-name", "error", "Two enabled OpModes share a name; the Robot Controller refuses to start"},
+	{"hardware-outside-init", "warning", "hardwareMap lookup after init (in loop/start or after waitForStart)"},
+	{"telemetry-never-updated", "warning", "LinearOpMode adds telemetry but never calls telemetry.update()"},
+}
+
+func lintRule(id string) LintRule {
+	for _, r := range lintRules {
+		if r.ID == id {
+			return r
+		}
+	}
+	return LintRule{ID: id, Severity: "warning"}
+}
+
+// LintFinding is one problem found by `lint`.
+type LintFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Message  string `json:"message"`
+}
+
+// javaSource is a TeamCode file prepared for linting. Code is Src with comments and string
+// literals blanked out (keeping offsets and line breaks) so the checks don't match inside them.
+type javaSource struct {
+	Path string // relative to the project, with forward slashes
+	Src  string
+	Code string
+	Kind string // "linear", "iterative" or "" for classes that aren't OpModes
+}
+
+// stripJava blanks out comments, strings and char literals, keeping newlines.
+func stripJava(src string) string {
+	b := []byte(src)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(b); i++ {
+			if b[i] != '\n' {
+				b[i] = ' '
+			}
+		}
+	}
+	for i := 0; i < len(b); i++ {
+		switch {
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			blank(i, i+end)
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 4
+			}
+			blank(i, i+end+4)
+			i += end + 3
+		case strings.HasPrefix(src[i:], `"""`):
+			end := strings.Index(src[i+3:], `"""`)
+			if end < 0 {
+				end = len(src) - i - 6
+			}
+			blank(i+3, i+3+end)
+			i += end + 5
+		case src[i] == '"' || src[i] == '\'':
+			quote := src[i]
+			j := i + 1
+			for j < len(src) && src[j] != quote && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			blank(i+1, j)
+			i = j
+		}
+	}
+	return string(b)
+}
+
+var opModeBase = regexp.MustCompile(`\bclass\s+\w+\s+extends\s+(LinearOpMode|OpMode)\b`)
+
+func newJavaSource(rel, src string) javaSource {
+	f := javaSource{Path: rel, Src: src, Code: stripJava(src)}
+	if m := opModeBase.FindStringSubmatch(f.Code); m != nil {
+		f.Kind = "iterative"
+		if m[1] == "LinearOpMode" {
+			f.Kind = "linear"
+		}
+	}
+	return f
+}
+
+func (f javaSource) line(offset int) int {
+	return strings.Count(f.Src[:offset], "\n") + 1
+}
+
+// matching returns the offset of the bracket closing the one at open, or -1.
+func matching(code string, open int) int {
+	closer := byte(')')
+	if code[open] == '{' {
+		closer = '}'
+	}
+	depth := 0
+	for i := open; i < len(code); i++ {
+		switch code[i] {
+		case code[open]:
+			depth++
+		case closer:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// matchingBack returns the offset of the '{' opening the '}' at close, or -1.
+func matchingBack(code string, close int) int {
+	depth := 0
+	for i := close; i >= 0; i-- {
+		switch code[i] {
+		case '}':
+			depth++
+		case '{':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// methodBody returns the start and end offsets of the body of a no-argument method, or -1, -1.
+func methodBody(code, name string) (int, int) {
+	re := regexp.MustCompile(`\bvoid\s+` + name + `\s*\(\s*\)\s*(?:throws\s+[\w.,\s]+)?\{`)
+	loc := re.FindStringIndex(code)
+	if loc == nil {
+		return -1, -1
+	}
+	open := loc[1] - 1
+	end := matching(code, open)
+	if end < 0 {
+		end = len(code)
+	}
+	return open, end
+}
+
+var (
+	sleepCall        = regexp.MustCompile(`\b(?:Thread|SystemClock)\s*\.\s*sleep\s*\(`)
+	waitForStartCall = regexp.MustCompile(`\b(?:waitForStart|opModeInInit|isStarted)\s*\(`)
+	runOpModeDecl    = regexp.MustCompile(`\bvoid\s+runOpMode\s*\(`)
+	whileKeyword     = regexp.MustCompile(`\bwhile\s*\(`)
+	activeCheck      = regexp.MustCompile(`\b(?:opModeIsActive|isStopRequested|opModeInInit|isStarted)\s*\(`)
+	hardwareMapUse   = regexp.MustCompile(`\bhardwareMap\s*\.`)
+	telemetryAdd     = regexp.MustCompile(`\btelemetry\s*\.\s*(?:addData|addLine)\s*\(`)
+	telemetryUpdate  = regexp.MustCompile(`\btelemetry\s*\.\s*update\s*\(`)
+)
+
+// lintFile runs the per-file rules.
+func lintFile(f javaSource) []LintFinding {
+	var out []LintFinding
+	add := func(rule string, offset int, msg string) {
+		out = append(out, LintFinding{Rule: rule, Severity: lintRule(rule).Severity, File: f.Path, Line: f.line(offset), Message: msg})
+	}
+
+	switch f.Kind {
+	case "iterative":
+		for _, loc := range sleepCall.FindAllStringIndex(f.Code, -1) {
+			add("sleep-in-iterative", loc[0], "sleep in an iterative OpMode blocks loop(); use an ElapsedTime timer instead")
+		}
+		for _, method := range []string{"start", "loop", "stop"} {
+			start, end := methodBody(f.Code, method)
+			if start < 0 {
+				continue
+			}
+			for _, loc := range hardwareMapUse.FindAllStringIndex(f.Code[start:end], -1) {
+				add("hardware-outside-init", start+loc[0], fmt.Sprintf("hardwareMap lookup in %s(); look devices up once in init()", method))
+			}
+		}
+
+	case "linear":
+		start, end := methodBody(f.Code, "runOpMode")
+		if start >= 0 {
+			body := f.Code[start:end]
+			if !waitForStartCall.MatchString(body) {
+				decl := runOpModeDecl.FindStringIndex(f.Code)
+				add("missing-wait-for-start", decl[0], "runOpMode() never calls waitForStart()")
+			}
+			if i := strings.Index(body, "waitForStart"); i >= 0 {
+				after := start + i
+				for _, use := range hardwareMapUse.FindAllStringIndex(f.Code[after:end], -1) {
+					add("hardware-outside-init", after+use[0], "hardwareMap lookup after waitForStart(); look devices up during init")
+				}
+			}
+
+			// Only loops in runOpMode itself; helper methods may loop over data without checking.
+			for _, loc := range whileKeyword.FindAllStringIndex(body, -1) {
+				kw, open := start+loc[0], start+loc[1]-1
+				condEnd := matching(f.Code, open)
+				if condEnd < 0 {
+					continue
+				}
+				cond := f.Code[open+1 : condEnd]
+				loop := loopBody(f.Code, kw, condEnd)
+				if !activeCheck.MatchString(cond) && !activeCheck.MatchString(loop) {
+					add("loop-without-active-check", kw, "loop doesn't check opModeIsActive(); pressing Stop won't end it")
+				}
+			}
+		}
+
+		if loc := telemetryAdd.FindStringIndex(f.Code); loc != nil && !telemetryUpdate.MatchString(f.Code) {
+			add("telemetry-never-updated", loc[0], "telemetry is added but telemetry.update() is never called, so nothing is shown")
+		}
+	}
+	return out
+}
+
+// loopBody returns the body of the while loop whose keyword starts at kw and whose condition ends at
+// condEnd. For do { } while (...); loops that is the block before the keyword.
+func loopBody(code string, kw, condEnd int) string {
+	rest := strings.TrimLeft(code[condEnd+1:], " \t\r\n")
+	switch {
+	case strings.HasPrefix(rest, "{"):
+		open := len(code) - len(rest)
+		if end := matching(code, open); end > 0 {
+			return code[open:end]
+		}
+	case strings.HasPrefix(rest, ";"):
+		before := strings.TrimRight(code[:kw], " \t\r\n")
+		if strings.HasSuffix(before, "}") {
+			if open := matchingBack(code, len(before)-1); open >= 0 {
+				return code[open:len(before)]
+			}
+		}
+	default:
+		if end := strings.IndexByte(rest, ';'); end >= 0 {
+			return rest[:end]
+		}
+	}
+	return ""
+}
+
+// lintDuplicateNames finds enabled OpModes that share a Driver Station name.
+func lintDuplicateNames(files []javaSource) []LintFinding {
+	type site struct {
+		file javaSource
+		line int
+	}
+	byName := map[string][]site{}
+	for _, f := range files {
+		base := strings.TrimSuffix(filepath.Base(f.Path), filepath.Ext(f.Path))
+		for _, m := range opModeAnnotation.FindAllStringSubmatchIndex(f.Code, -1) {
+			block, class := annotationBlock(f.Code, m[0])
+			if disabledAnnotation.MatchString(block) {
+				continue
+			}
+			name := valueOr(class, base)
+			if m[4] >= 0 {
+				// Read the name from the original source; string contents are blanked in Code.
+				if n := annotationName.FindStringSubmatch(f.Src[m[4]:m[5]]); n != nil {
+					name = n[1]
+				}
+			}
+			byName[name] = append(byName[name], site{f, f.line(m[0])})
+		}
+	}
+
+	var out []LintFinding
+	for name, sites := range byName {
+		if len(sites) < 2 {
+			continue
+		}
+		for i, s := range sites {
+			var others []string
+			for j, o := range sites {
+				if j != i {
+					others = append(others, fmt.Sprintf("%s:%d", o.file.Path, o.line))
+				}
+			}
+			out = append(out, LintFinding{
+				Rule: "duplicate-opmode-name", Severity: lintRule("duplicate-opmode-name").Severity,
+				File: s.file.Path, Line: s.line,
+				Message: fmt.Sprintf("OpMode name %q is also used at %s", name, strings.Join(others, ", ")),
+			})
+		}
+	}
+	return out
+}
+
+// lintIgnore matches suppression comments: "// ftc-lint:ignore rule-a,rule-b" at the end of the line or
+// on its own on the line above, and "// ftc-lint:ignore-file rule" anywhere in the file. Without rules, all are ignored.
+var lintIgnore = regexp.MustCompile(`//\s*ftc-lint:ignore(-file)?\b([ \t]+[\w\-, \t]+)?`)
+
+// suppressed reports whether a finding is silenced by a comment in its file.
+func suppressed(f javaSource, finding LintFinding) bool {
+	lines := strings.Split(f.Src, "\n")
+	matches := func(rules string) bool {
+		rules = strings.TrimSpace(rules)
+		if rules == "" {
+			return true
+		}
+		for _, r := range strings.FieldsFunc(rules, func(c rune) bool { return c == ',' || c == ' ' || c == '\t' }) {
+			if r == finding.Rule || r == "all" {
+				return true
+			}
+		}
+		return false
+	}
+	for i, line := range lines {
+		for _, m := range lintIgnore.FindAllStringSubmatch(line, -1) {
+			if !matches(m[2]) {
+				continue
+			}
+			// A comment on its own line covers the next line; a trailing comment only its own.
+			ownLine := strings.HasPrefix(strings.TrimSpace(line), "//")
+			if m[1] == "-file" || i+1 == finding.Line || (ownLine && i+2 == finding.Line) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lintProject lints the Java sources under TeamCode/src, sorted by file and line.
+func lintProject(projectPath string) ([]LintFinding, int, error) {
+	var files []javaSource
+	err := filepath.WalkDir(filepath.Join(projectPath, "TeamCode", "src"), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".java") {
+			return nil
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(projectPath, p)
+		files = append(files, newJavaSource(filepath.ToSlash(rel), string(b)))
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	byPath := map[string]javaSource{}
+	var all []LintFinding
+	for _, f := range files {
+		byPath[f.Path] = f
+		all = append(all, lintFile(f)...)
+	}
+	all = append(all, lintDuplicateNames(files)...)
+
+	var findings []LintFinding
+	for _, fd := range all {
+		if !suppressed(byPath[fd.File], fd) {
+			findings = append(findings, fd)
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, len(files), nil
+}
+
+// writeSARIF writes findings as a SARIF 2.1.0 log, the format GitHub code scanning reads.
+func writeSARIF(w io.Writer, findings []LintFinding) error {
+	type message struct {
+		Text string `json:"text"`
+	}
+	type rule struct {
+		ID               string  `json:"id"`
+		ShortDescription message `json:"shortDescription"`
+	}
+	type location struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region struct {
+				StartLine int `json:"startLine"`
+			} `json:"region"`
+		} `json:"physicalLocation"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations"`
+	}
+
+	var rules []rule
+	for _, r := range lintRules {
+		rules = append(rules, rule{r.ID, message{r.Description}})
+	}
+	results := []result{}
+	for _, f := range findings {
+		var loc location
+		loc.PhysicalLocation.ArtifactLocation.URI = f.File
+		loc.PhysicalLocation.Region.StartLine = f.Line
+		results = append(results, result{f.Rule, f.Severity, message{f.Message}, []location{loc}})
+	}
+
+	log := map[string]interface{}{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []interface{}{map[string]interface{}{
+			"tool": map[string]interface{}{"driver": map[string]interface{}{
+				"name":           "ftc-helper lint",
+				"informationUri": "https://github.com/" + selfUpdateRepo,
+				"rules":          rules,
+			}},
+			"results": results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// writeLintReport writes findings in the given format: text, json or sarif.
+func writeLintReport(w io.Writer, format string, findings []LintFinding, scanned int) error {
+	switch format {
+	case "text":
+		for _, f := range findings {
+			fmt.Fprintf(w, "%s:%d: %s: %s [%s]\n", f.File, f.Line, f.Severity, f.Message, f.Rule)
+		}
+		if len(findings) == 0 {
+			fmt.Fprintf(w, "No problems found in %d file(s).\n", scanned)
+		} else {
+			fmt.Fprintf(w, "%d problem(s) in %d file(s).\n", len(findings), scanned)
+		}
+		return nil
+	case "json":
+		if findings == nil {
+			findings = []LintFinding{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(findings)
+	case "sarif":
+		return writeSARIF(w, findings)
+	}
+	return fmt.Errorf("unknown format %q, expected text, json or sarif", format)
+}
+
+func lintRulesHelp() string {
+	var b strings.Builder
+	b.WriteString("Rules:\n")
+	for _, r := range lintRules {
+		fmt.Fprintf(&b, "  %-26s %s\n", r.ID, r.Description)
+	}
+	b.WriteString(`
+Silence a rule with a comment on the line or the line above:
+  // ftc-lint:ignore loop-without-active-check
+or for a whole file:
+  // ftc-lint:ignore-file sleep-in-iterative`)
+	return b.String()
+}
+
+// lintSeverityRank orders the --fail-on levels; "none" never fails.
+var lintSeverityRank = map[string]int{"none": 0, "warning": 1, "error": 2}
+
+// lintFails reports whether any finding is at least as severe as failOn.
+func lintFails(findings []LintFinding, failOn string) bool {
+	min := lintSeverityRank[failOn]
+	if min == 0 {
+		return false
+	}
+	for _, f := range findings {
+		if lintSeverityRank[f.Severity] >= min {
+			return true
+		}
+	}
+	return false
+}
+
+// lint: check TeamCode for common FTC mistakes
+var lintCmd = &cobra.Command{
+	Use:   "lint [project_name]",
+	Short: "Check TeamCode for common FTC mistakes",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		outPath, _ := cmd.Flags().GetString("out")
+		failOn, _ := cmd.Flags().GetString("fail-on")
+		if _, ok := lintSeverityRank[failOn]; !ok {
+			fmt.Printf("Unknown --fail-on level %q; use error, warning or none.\n", failOn)
+			return
+		}
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		findings, scanned, err := lintProject(projectPath)
+		if err != nil {
+			fmt.Println("Error reading TeamCode:", err)
+			return
+		}
+
+		if outPath == "" {
+			err = writeLintReport(os.Stdout, format, findings, scanned)
+		} else {
+			var f *os.File
+			if f, err = os.Create(outPath); err == nil {
+				err = writeLintReport(f, format, findings, scanned)
+				if cerr := f.Close(); err == nil {
+					err = cerr
+				}
+			}
+			if err == nil {
+				fmt.Printf("Wrote %d finding(s) to %s\n", len(findings), outPath)
+			}
+		}
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		if lintFails(findings, failOn) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	lintCmd.Long = "Scans the TeamCode Java sources for FTC-specific mistakes and exits non-zero when it finds errors (see --fail-on).\n\n" + lintRulesHelp()
+	lintCmd.Flags().StringP("format", "f", "text", "Output format: text, json or sarif")
+	lintCmd.Flags().StringP("out", "o", "", "Write the report to a file instead of stdout")
+	lintCmd.Flags().String("fail-on", "error", "Exit 1 on findings of this severity or worse: error, warning or none")
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestStripJava(t *testing.T) {
+	src := "a = \"while (x)\"; // sleep\n/* hardwareMap.\n */ c = 'x';"
+	got := stripJava(src)
+	if len(got) != len(src) || strings.Count(got, "\n") != 2 {
+		t.Fatalf("stripJava changed offsets: %q", got)
+	}
+	for _, gone := range []string{"while", "sleep", "hardwareMap", "'x'"} {
+		if strings.Contains(got, gone) {
+			t.Errorf("stripJava left %q in %q", gone, got)
+		}
+	}
+}
+
+func findingRules(findings []LintFinding) []string {
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.Rule)
+	}
+	return ids
+}
+
+func TestLintFile(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			"clean linear",
+			`public class Auto extends LinearOpMode {
+    public void runOpMode() throws InterruptedException {
+        motor = hardwareMap.get(DcMotor.class, "m");
+        waitForStart();
+        while (opModeIsActive()) {
+            telemetry.addData("x", 1);
+            telemetry.update();
+        }
+    }
+}`,
+			nil,
+		},
+		{
+			"linear mistakes",
+			`public class Auto extends LinearOpMode {
+    public void runOpMode() {
+        while (motor.isBusy()) {
+            telemetry.addData("busy", true);
+        }
+    }
+}`,
+			[]string{"missing-wait-for-start", "loop-without-active-check", "telemetry-never-updated"},
+		},
+		{
+			"check in body and do-while",
+			`public class Auto extends LinearOpMode {
+    public void runOpMode() {
+        waitForStart();
+        while (motor.isBusy()) {
+            if (isStopRequested()) return;
+        }
+        do {
+            idle();
+        } while (x < 3);
+        Servo s = hardwareMap.servo.get("claw");
+    }
+}`,
+			[]string{"loop-without-active-check", "hardware-outside-init"},
+		},
+		{
+			"loop in a helper method",
+			`public class Auto extends LinearOpMode {
+    public void runOpMode() {
+        waitForStart();
+        while (opModeIsActive()) { power = sum(readings); }
+    }
+    private double sum(double[] a) {
+        double t = 0; int i = 0;
+        while (i < a.length) { t += a[i++]; }
+        return t;
+    }
+}`,
+			nil,
+		},
+		{
+			"iterative",
+			`public class Tele extends OpMode {
+    public void init() { m = hardwareMap.get(DcMotor.class, "m"); }
+    public void loop() {
+        Servo s = hardwareMap.get(Servo.class, "s");
+        Thread.sleep(100);
+        while (x) { }
+    }
+}`,
+			[]string{"hardware-outside-init", "sleep-in-iterative"},
+		},
+		{
+			"not an opmode",
+			`public class Lift { void run() { while (busy()) { Thread.sleep(5); } } }`,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findingRules(lintFile(newJavaSource("T.java", tt.src)))
+			gotSet := map[string]bool{}
+			for _, r := range got {
+				gotSet[r] = true
+			}
+			wantSet := map[string]bool{}
+			for _, r := range tt.want {
+				wantSet[r] = true
+			}
+			if !reflect.DeepEqual(gotSet, wantSet) {
+				t.Errorf("rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintProject_DuplicatesAndSuppression(t *testing.T) {
+	root := makeTestProject(t, t.TempDir(), "robot")
+	dir := filepath.Join(root, "TeamCode", "src", "main", "java", "org", "firstinspires", "ftc", "teamcode")
+	files := map[string]string{
+		"A.java": "@TeleOp(name = \"Drive\")\npublic class A extends OpMode {}\n",
+		"B.java": "@Autonomous(name=\"Drive\")\npublic class B extends OpMode {}\n",
+		"C.java": "@TeleOp(name = \"Drive\")\n@Disabled\npublic class C extends OpMode {}\n",
+		"D.java": `public class D extends LinearOpMode {
+    public void runOpMode() {
+        waitForStart();
+        // ftc-lint:ignore loop-without-active-check
+        while (busy()) {}
+        while (busy()) {} // ftc-lint:ignore
+        while (busy()) {}
+    }
+}`,
+		"E.java": "// ftc-lint:ignore-file sleep-in-iterative\npublic class E extends OpMode { void loop() { Thread.sleep(1); } }\n",
+	}
+	for name, src := range files {
+		os.WriteFile(filepath.Join(dir, name), []byte(src), 0644)
+	}
+
+	findings, scanned, err := lintProject(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scanned != 6 {
+		t.Errorf("scanned %d files, want 6", scanned)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, filepath.Base(f.File)+":"+f.Rule)
+	}
+	want := []string{"A.java:duplicate-opmode-name", "B.java:duplicate-opmode-name", "D.java:loop-without-active-check"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+	if findings[2].Line != 7 {
+		t.Errorf("unsuppressed loop reported at line %d, want 7", findings[2].Line)
+	}
+}
+
+func TestLintFails(t *testing.T) {
+	warning := []LintFinding{{Rule: "loop-without-active-check", Severity: "warning"}}
+	withError := append(warning, LintFinding{Rule: "missing-wait-for-start", Severity: "error"})
+	tests := []struct {
+		findings []LintFinding
+		failOn   string
+		want     bool
+	}{
+		{nil, "warning", false},
+		{warning, "error", false},
+		{warning, "warning", true},
+		{withError, "error", true},
+		{withError, "none", false},
+	}
+	for _, tt := range tests {
+		if got := lintFails(tt.findings, tt.failOn); got != tt.want {
+			t.Errorf("lintFails(%d findings, %q) = %v, want %v", len(tt.findings), tt.failOn, got, tt.want)
+		}
+	}
+}
+
+func TestWriteLintReport(t *testing.T) {
+	findings := []LintFinding{{Rule: "missing-wait-for-start", Severity: "error", File: "TeamCode/src/A.java", Line: 3, Message: "m"}}
+
+	var buf bytes.Buffer
+	if err := writeLintReport(&buf, "text", findings, 2); err != nil || !strings.Contains(buf.String(), "TeamCode/src/A.java:3: error: m [missing-wait-for-start]") {
+		t.Errorf("text report = %q, %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	writeLintReport(&buf, "json", findings, 2)
+	var decoded []LintFinding
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || !reflect.DeepEqual(decoded, findings) {
+		t.Errorf("json report = %s, %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	writeLintReport(&buf, "sarif", findings, 2)
+	var sarif struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &sarif); err != nil {
+		t.Fatal(err)
+	}
+	r := sarif.Runs[0].Results[0]
+	if sarif.Version != "2.1.0" || r.RuleID != "missing-wait-for-start" || r.Level != "error" || r.Locations[0].PhysicalLocation.Region.StartLine != 3 {
+		t.Errorf("sarif report = %s", buf.String())
+	}
+
+	if err := writeLintReport(&buf, "xml", nil, 0); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestLintDuplicateNames_DisabledPerClass(t *testing.T) {
+	autos := newJavaSource("TeamCode/src/Autos.java", `public class Autos {
+    @Disabled
+    @Autonomous(name = "Left")
+    public static class OldLeft extends LinearOpMode {}
+
+    @Autonomous(name = "Right")
+    public static class Right extends LinearOpMode {}
+}`)
+	left := newJavaSource("TeamCode/src/Left.java", "@Autonomous(name = \"Left\")\npublic class Left extends LinearOpMode {}")
+	right := newJavaSource("TeamCode/src/Right.java", "@Autonomous(name = \"Right\")\npublic class Right extends LinearOpMode {}")
+
+	var got []string
+	for _, f := range lintDuplicateNames([]javaSource{autos, left, right}) {
+		got = append(got, fmt.Sprintf("%s:%d", filepath.Base(f.File), f.Line))
+	}
+	sort.Strings(got)
+	want := []string{"Autos.java:6", "Right.java:1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("duplicates = %v, want %v", got, want)
+	}
+}
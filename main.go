@@ -2,11 +2,9 @@ package main
 
 import (
 	"archive/zip"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
@@ -15,11 +13,12 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -57,11 +56,20 @@ var rootCmd = &cobra.Command{
 		if err := viper.ReadInConfig(); err == nil {
 			fmt.Println("Using config file:", viper.ConfigFileUsed())
 		}
-		workDir = viper.GetString("work_dir")
+		dir, err := resolveWorkDir(cmd.Flags().Changed("work-dir"))
+		if err != nil {
+			fmt.Println("Warning:", err)
+			dir = viper.GetString("work_dir")
+		}
+		workDir = dir
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		notifyUpdates(cmd)
 	},
 }
 
 func main() {
+	removeOldExecutable()
 	cobra.CheckErr(rootCmd.Execute())
 }
 
@@ -71,6 +79,8 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.ftc-helper.yaml)")
 	rootCmd.PersistentFlags().StringP("work-dir", "w", "", "working directory for projects")
 	viper.BindPFlag("work_dir", rootCmd.PersistentFlags().Lookup("work-dir"))
+	rootCmd.PersistentFlags().String("workspace", "", "named workspace to use for this command (see 'workspace list')")
+	viper.BindPFlag("workspace", rootCmd.PersistentFlags().Lookup("workspace"))
 
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(initCmd)
@@ -84,9 +94,40 @@ func init() {
 	rootCmd.AddCommand(downloadBambuCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(remoteCmd)
+	rootCmd.AddCommand(libCmd)
+	rootCmd.AddCommand(opmodeCmd)
+	rootCmd.AddCommand(workspaceCmd)
+	rootCmd.AddCommand(projectCmd)
+	rootCmd.AddCommand(cleanCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(sdkCmd)
+	rootCmd.AddCommand(checkUpdatesCmd)
+	rootCmd.AddCommand(selfUpdateCmd)
+	rootCmd.AddCommand(hwconfigCmd)
+	rootCmd.AddCommand(robotCmd)
+	rootCmd.AddCommand(samplesCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(fmtCmd)
+	rootCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(ciCmd)
+
+	pushCmd.Flags().Bool("fmt", false, "Format the code with 'fmt' before committing (always on with format_on_push)")
+
+	projectsCmd.Flags().Bool("all-workspaces", false, "List projects in every configured workspace")
+	projectsCmd.Flags().Bool("size", false, "Show disk usage of each project")
+	projectsCmd.Flags().Bool("refresh", false, "Look up the latest SDK release on GitHub instead of using the cached one")
+	projectsCmd.Flags().Bool("offline", false, "Don't check for a newer SDK release")
+	projectsCmd.Flags().MarkDeprecated("offline", "projects only uses the network with --refresh")
 
 	initCmd.Flags().StringP("project", "p", "", "Name of the new project directory")
 	initCmd.Flags().StringP("git", "g", "", "Git repository URL to set up as remote")
+	initCmd.Flags().String("create-repo", "", "Create this GitHub repository (owner/name, or name to use team.git_org), set it as remote and push")
+	initCmd.Flags().Bool("public", false, "Make the repository created with --create-repo public")
+	initCmd.Flags().Bool("skip-check", false, "Do not check that the --git remote is reachable")
+	initCmd.Flags().String("language", "", "Language for TeamCode: java or kotlin (default team.language or java)")
+	initCmd.Flags().StringSlice("lib", nil, "Libraries to add, e.g. --lib roadrunner,ftclib@2.1.1 (see 'lib list')")
 }
 
 func initConfig() {
@@ -99,41 +140,34 @@ func initConfig() {
 	}
 
 	viper.AutomaticEnv()
+	viper.SetEnvKeyReplacer(envKeyReplacer)
+	setConfigDefaults()
 
 	if err := viper.ReadInConfig(); err != nil {
-		fmt.Println("No config file found. Using default values.")
-		home, _ := os.UserHomeDir()
-		viper.SetDefault("work_dir", home+"/StudioProjects")
+		var notFound viper.ConfigFileNotFoundError
+		if errors.As(err, &notFound) || os.IsNotExist(err) {
+			fmt.Println("No config file found. Using default values. Run 'ftc-helper config init' to create one.")
+		} else {
+			fmt.Println("Error reading config file:", err)
+		}
 	}
 }
 
 // Mode 1: List Releases
 type Release struct {
-	TagName string `json:"tag_name"`
+	TagName string         `json:"tag_name"`
+	Assets  []ReleaseAsset `json:"assets,omitempty"`
 }
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Lists available FTC releases",
 	Run: func(cmd *cobra.Command, args []string) {
-		resp, err := http.Get("https://api.github.com/repos/FIRST-Tech-Challenge/FtcRobotController/releases")
+		releases, err := fetchReleases()
 		if err != nil {
 			fmt.Println("Error fetching releases:", err)
 			return
 		}
-		defer resp.Body.Close()
-
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			fmt.Println("Error reading response body:", err)
-			return
-		}
-
-		var releases []Release
-		if err := json.Unmarshal(body, &releases); err != nil {
-			fmt.Println("Error parsing JSON:", err)
-			return
-		}
 
 		fmt.Println("Available FTC releases:")
 		for _, release := range releases {
@@ -142,95 +176,245 @@ var listCmd = &cobra.Command{
 	},
 }
 
+// fetchReleases returns the FtcRobotController releases, newest first.
+func fetchReleases() ([]Release, error) {
+	var releases []Release
+	if err := githubDo("GET", "/repos/FIRST-Tech-Challenge/FtcRobotController/releases", nil, &releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// fetchRelease returns the FtcRobotController release with the given tag, including its assets.
+func fetchRelease(tag string) (*Release, error) {
+	var r Release
+	if err := githubDo("GET", "/repos/FIRST-Tech-Challenge/FtcRobotController/releases/tags/"+tag, nil, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
 // Mode 2: Initialize Project
 var initCmd = &cobra.Command{
 	Use:   "init [version]",
 	Short: "Initializes a new FTC project",
-	Args:  cobra.ExactArgs(1),
+	Long: `Initializes a new FTC project from an FtcRobotController release.
+
+Run without arguments to start an interactive wizard that walks through
+picking a release, project name, git remote, language and libraries.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		version := args[0]
-		projectName, _ := cmd.Flags().GetString("project")
-		gitURL, _ := cmd.Flags().GetString("git")
+		var opts initOptions
+		opts.ProjectName, _ = cmd.Flags().GetString("project")
+		opts.GitURL, _ = cmd.Flags().GetString("git")
+		opts.CreateRepo, _ = cmd.Flags().GetString("create-repo")
+		opts.Public, _ = cmd.Flags().GetBool("public")
+		opts.SkipCheck, _ = cmd.Flags().GetBool("skip-check")
+		opts.Language, _ = cmd.Flags().GetString("language")
+		opts.Libraries, _ = cmd.Flags().GetStringSlice("lib")
+
+		if len(args) == 0 {
+			if !isInteractive() {
+				fmt.Println("A release version is required when not running interactively. Use 'ftc-helper init <version> --project <name>'.")
+				return
+			}
+			wizardOpts, err := runInitWizard(os.Stdin, os.Stdout)
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			if wizardOpts == nil {
+				fmt.Println("Cancelled.")
+				return
+			}
+			opts = *wizardOpts
+			// The wizard already checked the remote.
+			opts.SkipCheck = true
+		} else {
+			opts.Version = args[0]
+			if opts.ProjectName == "" {
+				profile := teamProfile()
+				if profile.Number != "" {
+					season := profile.Season
+					if season == "" {
+						season = strconv.Itoa(ftcSeason(time.Now()))
+					}
+					opts.ProjectName = suggestProjectName(profile.Number, season)
+					fmt.Println("Using project name from team profile:", opts.ProjectName)
+				}
+			}
+		}
 
-		if projectName == "" {
-			fmt.Println("Project name is required. Use --project flag.")
-			return
+		if err := runInit(opts); err != nil {
+			fmt.Println("Error:", err)
 		}
+	},
+}
 
-		projectPath := filepath.Join(workDir, projectName)
-		zipURL := fmt.Sprintf("https://github.com/FIRST-Tech-Challenge/FtcRobotController/archive/refs/tags/%s.zip", version)
+// initOptions holds everything needed to create a project, whether it came from flags or the wizard.
+type initOptions struct {
+	Version     string
+	ProjectName string
+	GitURL      string
+	CreateRepo  string
+	Public      bool
+	SkipCheck   bool
+	Language    string
+	Libraries   []string
+}
+
+func runInit(opts initOptions) error {
+	version := opts.Version
+	projectName := opts.ProjectName
+	gitURL := opts.GitURL
+	createRepo := opts.CreateRepo
 
-		fmt.Printf("Downloading %s to %s...\n", zipURL, projectPath)
-		resp, err := http.Get(zipURL)
+	if projectName == "" {
+		return errors.New("project name is required. Use --project flag")
+	}
+
+	profile := teamProfile()
+	language := strings.ToLower(opts.Language)
+	if language == "" {
+		language = profile.Language
+	}
+	if language == "" {
+		language = "java"
+	}
+	if language != "java" && language != "kotlin" {
+		return fmt.Errorf("unsupported language %q, expected java or kotlin", opts.Language)
+	}
+
+	var libs []Library
+	for _, spec := range opts.Libraries {
+		lib, err := lookupLibrary(spec)
 		if err != nil {
-			fmt.Println("Error downloading file:", err)
-			return
+			return err
 		}
-		defer resp.Body.Close()
+		libs = append(libs, lib)
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			fmt.Printf("Error: Received status code %d\n", resp.StatusCode)
-			return
+	var repoOwner, repoName string
+	if createRepo != "" {
+		if !strings.Contains(createRepo, "/") && profile.GitOrg != "" {
+			createRepo = profile.GitOrg + "/" + createRepo
 		}
-
-		// Save the zip file
-		zipFile, err := ioutil.TempFile("", "ftc-*.zip")
+		if gitURL != "" {
+			return errors.New("use either --git or --create-repo, not both")
+		}
+		var err error
+		repoOwner, repoName, err = splitRepoSlug(createRepo)
 		if err != nil {
-			fmt.Println("Error creating temp file:", err)
-			return
+			return err
 		}
-		defer os.Remove(zipFile.Name())
-		defer zipFile.Close()
+	}
 
-		_, err = io.Copy(zipFile, resp.Body)
+	if gitURL != "" {
+		remote, err := normalizeRemote(gitURL)
 		if err != nil {
-			fmt.Println("Error writing to temp file:", err)
-			return
+			return err
 		}
+		if !opts.SkipCheck {
+			fmt.Println("Checking remote...")
+			if err := checkRemoteReachable(remote); err != nil {
+				return fmt.Errorf("%w\nUse --skip-check to add it anyway", err)
+			}
+		}
+		gitURL = remote
+	}
 
-		// Unzip the file
-		fmt.Println("Extracting files...")
-		if err := extractZip(zipFile.Name(), projectPath); err != nil {
-			fmt.Println("Error extracting zip:", err)
-			return
+	projectPath := filepath.Join(workDir, projectName)
+	zipPath, err := sdkArchive(version)
+	if err != nil {
+		return err
+	}
+
+	// Unzip the file
+	fmt.Printf("Extracting files to %s...\n", projectPath)
+	if err := extractZip(zipPath, projectPath); err != nil {
+		return fmt.Errorf("extracting zip: %w", err)
+	}
+
+	// Move contents up one level
+	subDir := filepath.Join(projectPath, fmt.Sprintf("FtcRobotController-%s", version))
+	subDirFixed := strings.Replace(subDir, "v", "", 1)
+	if _, err := os.Stat(subDirFixed); err == nil {
+		files, _ := os.ReadDir(subDirFixed)
+		for _, f := range files {
+			os.Rename(filepath.Join(subDirFixed, f.Name()), filepath.Join(projectPath, f.Name()))
 		}
+		os.Remove(subDirFixed)
+	}
 
-		// Move contents up one level
-		subDir := filepath.Join(projectPath, fmt.Sprintf("FtcRobotController-%s", version))
-		subDirFixed := strings.Replace(subDir, "v", "", 1)
-		if _, err := os.Stat(subDirFixed); err == nil {
-			files, _ := os.ReadDir(subDirFixed)
-			for _, f := range files {
-				os.Rename(filepath.Join(subDirFixed, f.Name()), filepath.Join(projectPath, f.Name()))
-			}
-			os.Remove(subDirFixed)
+	// Language and libraries
+	if language == "kotlin" {
+		fmt.Println("Enabling Kotlin...")
+		if err := enableKotlin(projectPath); err != nil {
+			fmt.Println("Error enabling Kotlin:", err)
+		}
+	}
+	var libNames []string
+	for _, lib := range libs {
+		fmt.Printf("Adding library %s %s...\n", lib.Name, lib.Version)
+		if err := addLibraryToProject(projectPath, lib); err != nil {
+			fmt.Printf("Error adding library %s: %v\n", lib.Name, err)
+			continue
 		}
+		libNames = append(libNames, lib.Name)
+	}
 
-		// Git setup
+	manifest := &ProjectManifest{
+		Name:       projectName,
+		SDKVersion: version,
+		Language:   language,
+		Libraries:  libNames,
+		Created:    time.Now().UTC(),
+	}
+	if err := writeManifest(projectPath, manifest); err != nil {
+		fmt.Println("Error writing project manifest:", err)
+	}
 
-		teamCodePath := filepath.Join(projectPath, "TeamCode", "src", "main", "java", "org", "firstinspires", "ftc", "teamcode")
-		fmt.Println("Initializing git repository...")
-		cmdGit := exec.Command("git", "init")
-		cmdGit.Dir = teamCodePath
-		if err := cmdGit.Run(); err != nil {
-			fmt.Println("Error initializing git repo:", err)
+	// Git setup
+
+	teamCodePath := filepath.Join(projectPath, "TeamCode", "src", "main", "java", "org", "firstinspires", "ftc", "teamcode")
+	fmt.Println("Initializing git repository...")
+	cmdGit := exec.Command("git", "init")
+	cmdGit.Dir = teamCodePath
+	if err := cmdGit.Run(); err != nil {
+		fmt.Println("Error initializing git repo:", err)
+	}
+
+	if createRepo != "" {
+		fmt.Printf("Creating GitHub repository %s/%s...\n", repoOwner, repoName)
+		repo, err := createGitHubRepo(repoOwner, repoName, !opts.Public)
+		if err != nil {
+			return fmt.Errorf("creating GitHub repository: %w", err)
 		}
+		fmt.Println("Created", repo.HTMLURL)
+		gitURL = repo.CloneURL
+	}
 
-		if gitURL != "" {
-			fmt.Printf("Setting up remote to %s...\n", gitURL)
-			remoteURL := gitURL
-			if !strings.HasPrefix(gitURL, "https://") && !strings.HasPrefix(gitURL, "git@") {
-				remoteURL = "https://" + gitURL
-			}
-			cmdGitRemote := exec.Command("git", "remote", "add", "origin", remoteURL)
-			cmdGitRemote.Dir = teamCodePath
-			if err := cmdGitRemote.Run(); err != nil {
-				fmt.Println("Error adding git remote:", err)
-			}
+	if gitURL != "" {
+		fmt.Printf("Setting up remote to %s...\n", gitURL)
+		if err := setRemote(teamCodePath, gitURL); err != nil {
+			fmt.Println("Error adding git remote:", err)
 		}
+	}
 
-		fmt.Println("Project setup complete!")
-	},
+	if createRepo != "" {
+		fmt.Println("Pushing initial commit...")
+		if err := pushInitialCommit(teamCodePath, gitURL); err != nil {
+			fmt.Println("Error pushing initial commit:", err)
+		}
+	}
+
+	fmt.Println("Project setup complete!")
+	return nil
+}
+
+// teamCodeDir returns the TeamCode package directory (the git repository) of a project in the work dir.
+func teamCodeDir(projectName string) string {
+	return filepath.Join(workDir, projectName, "TeamCode", "src", "main", "java", "org", "firstinspires", "ftc", "teamcode")
 }
 
 func extractZip(src, dest string) error {
@@ -410,6 +594,18 @@ var pushCmd = &cobra.Command{
 			return
 		}
 
+		if format, _ := cmd.Flags().GetBool("fmt"); format || viper.GetBool("format_on_push") {
+			fmt.Println("Formatting code...")
+			changed, err := formatProject(projectName, false)
+			if err != nil {
+				fmt.Println("Error formatting code:", err)
+				return
+			}
+			if len(changed) > 0 {
+				fmt.Printf("Formatted %d file(s).\n", len(changed))
+			}
+		}
+
 		fmt.Println("Staging changes...")
 		cmdAdd := exec.Command("git", "add", ".")
 		cmdAdd.Dir = teamCodePath
@@ -419,7 +615,8 @@ var pushCmd = &cobra.Command{
 		}
 
 		fmt.Println("Committing changes...")
-		cmdCommit := exec.Command("git", "commit", "-m", commitMessage)
+		commitArgs := append(teamProfile().gitIdentityArgs(), "commit", "-m", commitMessage)
+		cmdCommit := exec.Command("git", commitArgs...)
 		cmdCommit.Dir = teamCodePath
 		if err := cmdCommit.Run(); err != nil {
 			fmt.Println("Error committing changes:", err)
@@ -441,51 +638,124 @@ var projectsCmd = &cobra.Command{
 	Use:   "projects",
 	Short: "Lists all active local projects",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Active projects in:", workDir)
-		projects, err := os.ReadDir(workDir)
-		if err != nil {
-			fmt.Println("Error reading working directory:", err)
+		allWorkspaces, _ := cmd.Flags().GetBool("all-workspaces")
+		showSize, _ := cmd.Flags().GetBool("size")
+		refresh, _ := cmd.Flags().GetBool("refresh")
+
+		// Collect the projects to show, with the workspace they belong to.
+		type entry struct {
+			workspace, name, path string
+		}
+		var entries []entry
+		if allWorkspaces {
+			for _, ws := range allWorkspaceDirs() {
+				projects, err := findProjects(ws.Path)
+				if err != nil {
+					fmt.Printf("Error reading workspace %s: %v\n", ws.Name, err)
+					continue
+				}
+				for _, p := range projects {
+					entries = append(entries, entry{ws.Name, p, filepath.Join(ws.Path, p)})
+				}
+			}
+		} else {
+			fmt.Println("Active projects in:", workDir)
+			projects, err := findProjects(workDir)
+			if err != nil {
+				fmt.Println("Error reading working directory:", err)
+				return
+			}
+			for _, p := range projects {
+				entries = append(entries, entry{"", p, filepath.Join(workDir, p)})
+			}
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No active projects found.")
 			return
 		}
 
-		found := false
-		for _, p := range projects {
-			if p.IsDir() {
-				projectPath := filepath.Join(workDir, p.Name())
-				teamCodePath := filepath.Join(projectPath, "TeamCode", "src", "main", "java", "org", "firstinspires", "ftc", "teamcode")
+		var usage map[string]ProjectUsage
+		if showSize {
+			paths := make([]string, len(entries))
+			for i, e := range entries {
+				paths[i] = e.path
+			}
+			usage = projectUsage(paths)
+		}
 
-				if _, err := os.Stat(teamCodePath); err == nil {
-					fmt.Println("-", p.Name())
-					found = true
-				}
+		// Flag projects on an older SDK than the latest release known from the last update check, so
+		// listing never waits on the network unless asked to.
+		state := loadUpdateState()
+		if refresh {
+			if l, err := latestRelease(); err != nil {
+				fmt.Println("Warning: could not look up the latest release:", err)
+			} else {
+				state.LatestSDK = l
+				saveUpdateState(state)
 			}
 		}
+		latest := state.LatestSDK
 
-		if !found {
-			fmt.Println("No active projects found.")
+		if allWorkspaces {
+			fmt.Printf("%-16s %-24s %s\n", "WORKSPACE", "PROJECT", "SDK")
+		}
+		var total, build, outdated int64
+		for _, e := range entries {
+			sdk, _, err := detectSDKVersion(e.path)
+			if err != nil {
+				sdk = "unknown"
+			} else if latest != "" && compareVersions(sdk, latest) < 0 {
+				sdk += " (update available)"
+				outdated++
+			}
+			line := fmt.Sprintf("- %-24s %s", e.name, sdk)
+			if allWorkspaces {
+				line = fmt.Sprintf("%-16s %-24s %s", e.workspace, e.name, sdk)
+			}
+			if showSize {
+				u := usage[e.path]
+				if u.Err != nil {
+					line = fmt.Sprintf("%-64s error: %v", line, u.Err)
+				} else {
+					line = fmt.Sprintf("%-64s %10s  (build outputs %s)", line, humanSize(u.Total), humanSize(u.Build))
+					total += u.Total
+					build += u.Build
+				}
+			}
+			fmt.Println(line)
+		}
+		switch {
+		case outdated > 0:
+			fmt.Printf("%d project(s) use an SDK older than the latest release %s.\n", outdated, latest)
+		case latest == "" && !refresh:
+			fmt.Println("Latest release unknown; run 'ftc-helper projects --refresh' to check for SDK updates.")
+		}
+		if showSize {
+			fmt.Printf("Total %s, of which %s can be removed with 'ftc-helper clean'\n", humanSize(total), humanSize(build))
 		}
 	},
 }
 
-// config: print current configuration as YAML
-var configCmd = &cobra.Command{
-	Use:   "config",
-	Short: "Print the current configuration as YAML",
-	Run: func(cmd *cobra.Command, args []string) {
-		settings := viper.AllSettings()
-		// Marshal to JSON first, then convert to YAML for pretty output
-		b, err := json.Marshal(settings)
-		if err != nil {
-			fmt.Println("Error marshaling config to JSON:", err)
-			return
-		}
-		y, err := yaml.JSONToYAML(b)
-		if err != nil {
-			fmt.Println("Error converting config to YAML:", err)
-			return
+// findProjects returns the names of the directories in dir that contain a TeamCode package.
+func findProjects(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []string
+	for _, p := range entries {
+		if p.IsDir() {
+			projectPath := filepath.Join(dir, p.Name())
+			teamCodePath := filepath.Join(projectPath, "TeamCode", "src", "main", "java", "org", "firstinspires", "ftc", "teamcode")
+
+			if _, err := os.Stat(teamCodePath); err == nil {
+				projects = append(projects, p.Name())
+			}
 		}
-		fmt.Println(string(y))
-	},
+	}
+	return projects, nil
 }
 
 // download-git: download the latest Git for Windows installer (64-bit) from GitHub releases
@@ -522,17 +792,6 @@ func init() {
 
 // findLatestGitForWindows queries the Git for Windows GitHub releases and finds the latest 64-bit installer URL and filename.
 func findLatestGitForWindows() (string, string, error) {
-	apiURL := "https://api.github.com/repos/git-for-windows/git/releases/latest"
-	resp, err := http.Get(apiURL)
-	if err != nil {
-		return "", "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
 	var data struct {
 		Assets []struct {
 			Name               string `json:"name"`
@@ -540,12 +799,7 @@ func findLatestGitForWindows() (string, string, error) {
 		} `json:"assets"`
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return "", "", err
-	}
-
-	if err := json.Unmarshal(body, &data); err != nil {
+	if err := githubDo("GET", "/repos/git-for-windows/git/releases/latest", nil, &data); err != nil {
 		return "", "", err
 	}
 
@@ -590,6 +844,7 @@ var downloadRevCmd = &cobra.Command{
 			fmt.Println("Error downloading REV Hardware Client installer:", err)
 			return
 		}
+		recordREVDownload(filename)
 		runBinary(out)
 
 	},
@@ -824,6 +1079,7 @@ var downloadAllCmd = &cobra.Command{
 			fmt.Println("Error downloading REV Hardware Client installer:", err)
 			return
 		}
+		recordREVDownload(filename)
 		runBinary(filename)
 
 		platform := detectStudioPlatform()
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// manifestFile is written to the root of every project created by ftc-helper.
+const manifestFile = ".ftc-helper-project.yaml"
+
+// ProjectManifest records how a project was created so later commands don't have to guess.
+type ProjectManifest struct {
+	Name           string    `json:"name,omitempty"`
+	SDKVersion     string    `json:"sdk_version"`
+	Language       string    `json:"language,omitempty"`
+	Libraries      []string  `json:"libraries,omitempty"`
+	Created        time.Time `json:"created"`
+	DuplicatedFrom string    `json:"duplicated_from,omitempty"`
+	HardwareConfig string    `json:"hardware_config,omitempty"`
+}
+
+// readManifest loads the manifest from a project directory.
+func readManifest(projectPath string) (*ProjectManifest, error) {
+	b, err := os.ReadFile(filepath.Join(projectPath, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var m ProjectManifest
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// writeManifest saves the manifest to a project directory.
+func writeManifest(projectPath string, m *ProjectManifest) error {
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(projectPath, manifestFile), b, 0644)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+var javaOpModeTemplate = template.Must(template.New("java").Parse(`{{.Header}}package org.firstinspires.ftc.teamcode;
+
+import com.qualcomm.robotcore.eventloop.opmode.{{.Annotation}};
+import com.qualcomm.robotcore.eventloop.opmode.LinearOpMode;
+
+@{{.Annotation}}(name = "{{.Name}}", group = "{{.Group}}")
+public class {{.Name}} extends LinearOpMode {
+
+    @Override
+    public void runOpMode() {
+        // Initialize hardware here.
+
+        telemetry.addData("Status", "Initialized");
+        telemetry.update();
+
+        waitForStart();
+
+        while (opModeIsActive()) {
+            telemetry.addData("Status", "Running");
+            telemetry.update();
+        }
+    }
+}
+`))
+
+var kotlinOpModeTemplate = template.Must(template.New("kotlin").Parse(`{{.Header}}package org.firstinspires.ftc.teamcode
+
+import com.qualcomm.robotcore.eventloop.opmode.{{.Annotation}}
+import com.qualcomm.robotcore.eventloop.opmode.LinearOpMode
+
+@{{.Annotation}}(name = "{{.Name}}", group = "{{.Group}}")
+class {{.Name}} : LinearOpMode() {
+
+    override fun runOpMode() {
+        // Initialize hardware here.
+
+        telemetry.addData("Status", "Initialized")
+        telemetry.update()
+
+        waitForStart()
+
+        while (opModeIsActive()) {
+            telemetry.addData("Status", "Running")
+            telemetry.update()
+        }
+    }
+}
+`))
+
+var javaIdentifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sourceHeader formats the team profile as a block comment for generated files, or "" if the profile is empty.
+func sourceHeader(fileName string, p TeamProfile) string {
+	lines := p.header()
+	if len(lines) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("/*\n * " + fileName + "\n *\n")
+	for _, l := range lines {
+		b.WriteString(" * " + l + "\n")
+	}
+	b.WriteString(" */\n\n")
+	return b.String()
+}
+
+// renderOpMode returns the source for a new LinearOpMode.
+func renderOpMode(name, kind, language string, p TeamProfile) (string, error) {
+	if !javaIdentifierRe.MatchString(name) {
+		return "", fmt.Errorf("%q is not a valid class name", name)
+	}
+
+	annotation := "TeleOp"
+	switch strings.ToLower(kind) {
+	case "teleop", "":
+	case "auto", "autonomous":
+		annotation = "Autonomous"
+	default:
+		return "", fmt.Errorf("unknown OpMode type %q, expected teleop or autonomous", kind)
+	}
+
+	tmpl, ext := javaOpModeTemplate, ".java"
+	if language == "kotlin" {
+		tmpl, ext = kotlinOpModeTemplate, ".kt"
+	}
+
+	group := p.Name
+	if group == "" {
+		group = "TeamCode"
+	}
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, map[string]string{
+		"Header":     sourceHeader(name+ext, p),
+		"Annotation": annotation,
+		"Name":       name,
+		"Group":      group,
+	})
+	return buf.String(), err
+}
+
+// opmode: scaffold new OpModes in TeamCode
+var opmodeCmd = &cobra.Command{
+	Use:   "opmode",
+	Short: "Create OpModes in a project",
+}
+
+var opmodeNewCmd = &cobra.Command{
+	Use:   "new [project_name] [ClassName]",
+	Short: "Create a new LinearOpMode in the project's TeamCode package",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName, name := args[0], args[1]
+		kind, _ := cmd.Flags().GetString("type")
+		teamCodePath := teamCodeDir(projectName)
+
+		if _, err := os.Stat(teamCodePath); os.IsNotExist(err) {
+			fmt.Println("Project not found or TeamCode directory does not exist.")
+			return
+		}
+
+		profile := teamProfile()
+		language := profile.Language
+		if m, err := readManifest(filepath.Join(workDir, projectName)); err == nil && m.Language != "" {
+			language = m.Language
+		}
+
+		src, err := renderOpMode(name, kind, language, profile)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		ext := ".java"
+		if language == "kotlin" {
+			ext = ".kt"
+		}
+		out := filepath.Join(teamCodePath, name+ext)
+		if _, err := os.Stat(out); err == nil {
+			fmt.Println("File already exists:", out)
+			return
+		}
+		if err := os.WriteFile(out, []byte(src), 0644); err != nil {
+			fmt.Println("Error writing OpMode:", err)
+			return
+		}
+		fmt.Println("Created", out)
+	},
+}
+
+func init() {
+	opmodeNewCmd.Flags().StringP("type", "t", "teleop", "OpMode type: teleop or autonomous")
+	opmodeCmd.AddCommand(opmodeNewCmd)
+}
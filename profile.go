@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// TeamProfile is the "team" section of the config file. It is shared by everyone using
+// the machine, so it is used for defaults rather than anything personal.
+type TeamProfile struct {
+	Number      string
+	Name        string
+	Season      string
+	GitOrg      string
+	SDKVersion  string
+	Language    string
+	AuthorName  string
+	AuthorEmail string
+}
+
+// teamProfile reads the team section of the configuration.
+func teamProfile() TeamProfile {
+	return TeamProfile{
+		Number:      viper.GetString("team.number"),
+		Name:        viper.GetString("team.name"),
+		Season:      viper.GetString("team.season"),
+		GitOrg:      viper.GetString("team.git_org"),
+		SDKVersion:  viper.GetString("team.sdk_version"),
+		Language:    strings.ToLower(viper.GetString("team.language")),
+		AuthorName:  viper.GetString("team.author_name"),
+		AuthorEmail: viper.GetString("team.author_email"),
+	}
+}
+
+// gitIdentityArgs returns "-c user.name=... -c user.email=..." for the configured author, so
+// commits made on shared lab laptops are attributed to the team rather than whoever set up git.
+func (p TeamProfile) gitIdentityArgs() []string {
+	var args []string
+	if p.AuthorName != "" {
+		args = append(args, "-c", "user.name="+p.AuthorName)
+	}
+	if p.AuthorEmail != "" {
+		args = append(args, "-c", "user.email="+p.AuthorEmail)
+	}
+	return args
+}
+
+// header returns the lines identifying the team, used at the top of generated source files.
+func (p TeamProfile) header() []string {
+	var lines []string
+	switch {
+	case p.Number != "" && p.Name != "":
+		lines = append(lines, fmt.Sprintf("FTC Team %s - %s", p.Number, p.Name))
+	case p.Number != "":
+		lines = append(lines, "FTC Team "+p.Number)
+	case p.Name != "":
+		lines = append(lines, p.Name)
+	}
+	if p.Season != "" {
+		lines = append(lines, "Season "+p.Season)
+	}
+	if p.AuthorName != "" {
+		author := p.AuthorName
+		if p.AuthorEmail != "" {
+			author += " <" + p.AuthorEmail + ">"
+		}
+		lines = append(lines, "Author: "+author)
+	}
+	return lines
+}
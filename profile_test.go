@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTeamProfile_GitIdentityArgs(t *testing.T) {
+	p := TeamProfile{AuthorName: "Team 12345", AuthorEmail: "robots@example.com"}
+	got := strings.Join(p.gitIdentityArgs(), " ")
+	want := "-c user.name=Team 12345 -c user.email=robots@example.com"
+	if got != want {
+		t.Fatalf("gitIdentityArgs = %q, want %q", got, want)
+	}
+	if args := (TeamProfile{}).gitIdentityArgs(); len(args) != 0 {
+		t.Fatalf("empty profile should not override identity, got %v", args)
+	}
+}
+
+func TestRenderOpMode(t *testing.T) {
+	p := TeamProfile{Number: "12345", Name: "Gear Grinders", Season: "2025", AuthorName: "Sam"}
+
+	src, err := renderOpMode("DriveTeleOp", "teleop", "java", p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"/*\n * DriveTeleOp.java\n *\n * FTC Team 12345 - Gear Grinders\n * Season 2025\n * Author: Sam\n */\n\npackage org.firstinspires.ftc.teamcode;",
+		`@TeleOp(name = "DriveTeleOp", group = "Gear Grinders")`,
+		"public class DriveTeleOp extends LinearOpMode {",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("java source missing %q:\n%s", want, src)
+		}
+	}
+
+	src, err = renderOpMode("LeftAuto", "autonomous", "kotlin", TeamProfile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(src, "package org.firstinspires.ftc.teamcode\n") {
+		t.Errorf("empty profile should produce no header:\n%s", src)
+	}
+	if !strings.Contains(src, `@Autonomous(name = "LeftAuto", group = "TeamCode")`) || !strings.Contains(src, "class LeftAuto : LinearOpMode()") {
+		t.Errorf("unexpected kotlin source:\n%s", src)
+	}
+
+	if _, err := renderOpMode("1Bad", "teleop", "java", p); err == nil {
+		t.Error("expected error for invalid class name")
+	}
+	if _, err := renderOpMode("Good", "vision", "java", p); err == nil {
+		t.Error("expected error for unknown type")
+	}
+}
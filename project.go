@@ -0,0 +1,474 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// rootBuildOutputs and moduleBuildOutputs are where Gradle and Android Studio put regenerable files,
+// at the top of the project and inside each Gradle module.
+var (
+	rootBuildOutputs   = []string{"build", ".gradle", ".kotlin", "captures"}
+	moduleBuildOutputs = []string{"build", ".cxx", ".externalNativeBuild"}
+)
+
+// buildOutputs returns the build output directories of a project, relative to its root with forward
+// slashes. Only the known locations are checked, so a source package that happens to be called
+// "build" or "captures" is never treated as output.
+func buildOutputs(projectPath string) (map[string]bool, error) {
+	isDir := func(rel string) bool {
+		info, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(rel)))
+		return err == nil && info.IsDir()
+	}
+	outputs := map[string]bool{}
+	for _, name := range rootBuildOutputs {
+		if isDir(name) {
+			outputs[name] = true
+		}
+	}
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() || outputs[e.Name()] || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		module := filepath.Join(projectPath, e.Name())
+		if _, err := os.Stat(filepath.Join(module, "build.gradle")); err != nil {
+			if _, err := os.Stat(filepath.Join(module, "build.gradle.kts")); err != nil {
+				continue
+			}
+		}
+		for _, name := range moduleBuildOutputs {
+			if rel := e.Name() + "/" + name; isDir(rel) {
+				outputs[rel] = true
+			}
+		}
+	}
+	return outputs, nil
+}
+
+// isBuildOutput reports whether a path relative to the project root is a regenerable build output.
+// local.properties is included because it only holds the machine-specific Android SDK path.
+func isBuildOutput(outputs map[string]bool, rel string, d fs.DirEntry) bool {
+	if d.IsDir() {
+		return outputs[filepath.ToSlash(rel)]
+	}
+	return filepath.ToSlash(rel) == "local.properties"
+}
+
+// copyProject copies src to dst, skipping build outputs.
+func copyProject(src, dst string) error {
+	outputs, err := buildOutputs(src)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		if rel != "." && isBuildOutput(outputs, rel, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(p, target, info.Mode().Perm())
+		}
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// zipProject writes the project to a zip file under a top-level directory named after the project,
+// skipping build outputs.
+func zipProject(src, dst string) error {
+	outputs, err := buildOutputs(src)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(f)
+
+	base := filepath.Base(src)
+	err = filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if isBuildOutput(outputs, rel, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(filepath.Join(base, rel))
+		if info.IsDir() {
+			hdr.Name += "/"
+			_, err = zw.CreateHeader(hdr)
+			return err
+		}
+		hdr.Method = zip.Deflate
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		in, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		_, err = io.Copy(w, in)
+		return err
+	})
+	if err != nil {
+		zw.Close()
+		f.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// archiveDir returns where `project archive` stores zips (archive_dir, default <work dir>/_archive).
+func archiveDir() string {
+	if d := viper.GetString("archive_dir"); d != "" {
+		return d
+	}
+	return filepath.Join(workDir, "_archive")
+}
+
+// unpushedWork describes local changes in a TeamCode repository that would be lost if it were deleted.
+// It returns an empty slice when everything is committed and pushed.
+func unpushedWork(teamCodePath string) []string {
+	var warnings []string
+	if _, err := os.Stat(filepath.Join(teamCodePath, ".git")); err != nil {
+		return []string{"TeamCode is not a git repository; the code exists only on this computer"}
+	}
+
+	if out, err := exec.Command("git", "-C", teamCodePath, "status", "--porcelain").Output(); err == nil {
+		if n := countLines(string(out)); n > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d uncommitted change(s)", n))
+		}
+	}
+
+	remotes, _ := exec.Command("git", "-C", teamCodePath, "remote").Output()
+	if strings.TrimSpace(string(remotes)) == "" {
+		warnings = append(warnings, "no git remote is configured; commits exist only on this computer")
+		return warnings
+	}
+	if out, err := exec.Command("git", "-C", teamCodePath, "log", "--branches", "--not", "--remotes", "--oneline").Output(); err == nil {
+		if n := countLines(string(out)); n > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d commit(s) not pushed to the remote", n))
+		}
+	}
+	return warnings
+}
+
+func countLines(s string) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	return strings.Count(s, "\n") + 1
+}
+
+// askConfirm asks a yes/no question on stdin, defaulting to no.
+func askConfirm(prompt string) bool {
+	fmt.Printf("%s (y/N) ", prompt)
+	var response string
+	fmt.Scanln(&response)
+	return strings.ToLower(response) == "y"
+}
+
+// existingProject returns the path of a project in the work dir, or an error if it is missing.
+func existingProject(name string) (string, error) {
+	if err := validProjectName(name); err != nil {
+		return "", err
+	}
+	projectPath := filepath.Join(workDir, name)
+	if _, err := os.Stat(teamCodeDir(name)); err != nil {
+		return "", fmt.Errorf("project not found or TeamCode directory does not exist: %s", name)
+	}
+	return projectPath, nil
+}
+
+// validProjectName rejects names that would escape the work dir.
+func validProjectName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid project name %q", name)
+	}
+	return nil
+}
+
+// freshGitHistory initializes a new repository in dir and commits its contents.
+func freshGitHistory(dir, message string) error {
+	cmdInit := exec.Command("git", "init")
+	cmdInit.Dir = dir
+	if err := cmdInit.Run(); err != nil {
+		return err
+	}
+
+	cmdAdd := exec.Command("git", "add", ".")
+	cmdAdd.Dir = dir
+	if err := cmdAdd.Run(); err != nil {
+		return err
+	}
+
+	commitArgs := append(teamProfile().gitIdentityArgs(), "commit", "-q", "-m", message)
+	cmdCommit := exec.Command("git", commitArgs...)
+	cmdCommit.Dir = dir
+	if out, err := cmdCommit.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// project: manage existing projects
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Rename, archive, delete or duplicate projects",
+}
+
+var projectRenameCmd = &cobra.Command{
+	Use:   "rename [project_name] [new_name]",
+	Short: "Rename a project",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if err := validProjectName(args[1]); err != nil {
+			fmt.Println(err)
+			return
+		}
+		newPath := filepath.Join(workDir, args[1])
+		if _, err := os.Stat(newPath); err == nil {
+			fmt.Println("A project with that name already exists:", args[1])
+			return
+		}
+
+		if err := os.Rename(projectPath, newPath); err != nil {
+			fmt.Println("Error renaming project:", err)
+			return
+		}
+		if m, err := readManifest(newPath); err == nil {
+			m.Name = args[1]
+			if err := writeManifest(newPath, m); err != nil {
+				fmt.Println("Error updating project manifest:", err)
+			}
+		}
+		fmt.Printf("Renamed '%s' to '%s'\n", args[0], args[1])
+	},
+}
+
+var projectArchiveCmd = &cobra.Command{
+	Use:   "archive [project_name]",
+	Short: "Zip a project (without build outputs) into the archive directory and remove it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		keep, _ := cmd.Flags().GetBool("keep")
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		dir := archiveDir()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fmt.Println("Error creating archive directory:", err)
+			return
+		}
+		out := filepath.Join(dir, fmt.Sprintf("%s-%s.zip", args[0], time.Now().Format("20060102-150405")))
+
+		fmt.Printf("Archiving '%s' to %s...\n", args[0], out)
+		if err := zipProject(projectPath, out); err != nil {
+			fmt.Println("Error archiving project:", err)
+			return
+		}
+
+		if keep {
+			fmt.Println("Archive complete.")
+			return
+		}
+		if err := os.RemoveAll(projectPath); err != nil {
+			fmt.Println("Error removing project after archiving:", err)
+			return
+		}
+		fmt.Println("Archive complete. The project was removed from", workDir)
+	},
+}
+
+var projectDeleteCmd = &cobra.Command{
+	Use:   "delete [project_name]",
+	Short: "Delete a project after confirmation",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		yes, _ := cmd.Flags().GetBool("yes")
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		warnings := unpushedWork(teamCodeDir(args[0]))
+		for _, w := range warnings {
+			fmt.Println("Warning:", w)
+		}
+
+		if !yes {
+			prompt := fmt.Sprintf("Permanently delete %s?", projectPath)
+			if len(warnings) > 0 {
+				prompt = fmt.Sprintf("Unsaved work will be lost. Permanently delete %s?", projectPath)
+			}
+			if !askConfirm(prompt) {
+				fmt.Println("Cancelled.")
+				return
+			}
+		}
+
+		if err := os.RemoveAll(projectPath); err != nil {
+			fmt.Println("Error deleting project:", err)
+			return
+		}
+		fmt.Printf("Deleted '%s'\n", args[0])
+	},
+}
+
+var projectDuplicateCmd = &cobra.Command{
+	Use:   "duplicate [project_name] [new_name]",
+	Short: "Copy a project with a fresh git history, or on a new branch with --branch",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		branch, _ := cmd.Flags().GetString("branch")
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if err := validProjectName(args[1]); err != nil {
+			fmt.Println(err)
+			return
+		}
+		newPath := filepath.Join(workDir, args[1])
+		if _, err := os.Stat(newPath); err == nil {
+			fmt.Println("A project with that name already exists:", args[1])
+			return
+		}
+
+		fmt.Printf("Copying '%s' to '%s'...\n", args[0], args[1])
+		if err := copyProject(projectPath, newPath); err != nil {
+			fmt.Println("Error copying project:", err)
+			os.RemoveAll(newPath)
+			return
+		}
+
+		teamCodePath := teamCodeDir(args[1])
+		if branch != "" {
+			cmdGit := exec.Command("git", "checkout", "-b", branch)
+			cmdGit.Dir = teamCodePath
+			if out, err := cmdGit.CombinedOutput(); err != nil {
+				fmt.Printf("Error creating branch %s: %v: %s\n", branch, err, strings.TrimSpace(string(out)))
+			}
+		} else {
+			os.RemoveAll(filepath.Join(teamCodePath, ".git"))
+			if err := freshGitHistory(teamCodePath, "Duplicated from "+args[0]); err != nil {
+				fmt.Println("Error creating git history:", err)
+			}
+		}
+
+		if m, err := readManifest(newPath); err == nil {
+			m.Name = args[1]
+			m.DuplicatedFrom = args[0]
+			m.Created = time.Now().UTC()
+			if err := writeManifest(newPath, m); err != nil {
+				fmt.Println("Error updating project manifest:", err)
+			}
+		}
+		fmt.Printf("Created '%s'\n", args[1])
+	},
+}
+
+func init() {
+	projectArchiveCmd.Flags().Bool("keep", false, "Keep the project after archiving it")
+	projectDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	projectDuplicateCmd.Flags().StringP("branch", "b", "", "Keep the git history and switch the copy to this new branch")
+	projectCmd.AddCommand(projectRenameCmd)
+	projectCmd.AddCommand(projectArchiveCmd)
+	projectCmd.AddCommand(projectDeleteCmd)
+	projectCmd.AddCommand(projectDuplicateCmd)
+}
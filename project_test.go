@@ -0,0 +1,223 @@
+package main
+
+import (
+	"archive/zip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// makeTestProject creates a minimal project with build outputs in dir/name.
+func makeTestProject(t *testing.T, dir, name string) string {
+	t.Helper()
+	root := filepath.Join(dir, name)
+	files := map[string]string{
+		"build.gradle":          "// root",
+		"TeamCode/build.gradle": "// module",
+		"local.properties":      "sdk.dir=/home/me/Android",
+		"TeamCode/src/main/java/org/firstinspires/ftc/teamcode/Drive.java": "class Drive {}",
+		"TeamCode/build/outputs/apk/debug/TeamCode-debug.apk":              "apk",
+		".gradle/8.9/fileHashes/fileHashes.bin":                            "cache",
+	}
+	for p, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// addBuildPackage adds a source package named "build" to a test project, which must not be mistaken
+// for a build output.
+func addBuildPackage(t *testing.T, root string) {
+	t.Helper()
+	p := filepath.Join(root, "TeamCode", "src", "main", "java", "org", "firstinspires", "ftc", "teamcode", "build", "Lift.java")
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte("class Lift {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyProject_SkipsBuildOutputs(t *testing.T) {
+	dir := t.TempDir()
+	src := makeTestProject(t, dir, "robot")
+	addBuildPackage(t, src)
+	dst := filepath.Join(dir, "copy")
+
+	if err := copyProject(src, dst); err != nil {
+		t.Fatalf("copyProject: %v", err)
+	}
+	for _, p := range []string{"Drive.java", "build/Lift.java"} {
+		if _, err := os.Stat(filepath.Join(dst, "TeamCode", "src", "main", "java", "org", "firstinspires", "ftc", "teamcode", filepath.FromSlash(p))); err != nil {
+			t.Errorf("source file not copied: %v", err)
+		}
+	}
+	for _, p := range []string{"TeamCode/build", ".gradle", "local.properties"} {
+		if _, err := os.Stat(filepath.Join(dst, filepath.FromSlash(p))); err == nil {
+			t.Errorf("%s should not be copied", p)
+		}
+	}
+}
+
+func TestZipProject(t *testing.T) {
+	dir := t.TempDir()
+	src := makeTestProject(t, dir, "robot")
+	addBuildPackage(t, src)
+	out := filepath.Join(dir, "robot.zip")
+
+	if err := zipProject(src, out); err != nil {
+		t.Fatalf("zipProject: %v", err)
+	}
+	r, err := zip.OpenReader(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	var files []string
+	for _, f := range r.File {
+		if !strings.HasSuffix(f.Name, "/") {
+			files = append(files, f.Name)
+		}
+	}
+	sort.Strings(files)
+	want := []string{
+		"robot/TeamCode/build.gradle",
+		"robot/TeamCode/src/main/java/org/firstinspires/ftc/teamcode/Drive.java",
+		"robot/TeamCode/src/main/java/org/firstinspires/ftc/teamcode/build/Lift.java",
+		"robot/build.gradle",
+	}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Fatalf("zip contents = %v, want %v", files, want)
+	}
+}
+
+func TestUnpushedWork(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+
+	if w := unpushedWork(dir); len(w) != 1 || !strings.Contains(w[0], "not a git repository") {
+		t.Fatalf("plain directory warnings = %v", w)
+	}
+
+	exec.Command("git", "-C", dir, "init", "-q").Run()
+	os.WriteFile(filepath.Join(dir, "Drive.java"), []byte("class Drive {}"), 0644)
+	w := unpushedWork(dir)
+	if len(w) != 2 || w[0] != "1 uncommitted change(s)" || !strings.Contains(w[1], "no git remote") {
+		t.Fatalf("new repository warnings = %v", w)
+	}
+}
+
+func TestValidProjectName(t *testing.T) {
+	for _, name := range []string{"", ".", "..", "a/b", `a\b`, "a/../b", "../other"} {
+		if validProjectName(name) == nil {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+	if err := validProjectName("2025-12345"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestExistingProject_RejectsPathsOutsideWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	makeTestProject(t, dir, "other")
+	workDir = filepath.Join(dir, "work")
+	makeTestProject(t, workDir, "robot")
+
+	if _, err := existingProject("robot"); err != nil {
+		t.Errorf("existingProject(robot): %v", err)
+	}
+	for _, name := range []string{"../other", "robot/../../other", ".", ""} {
+		if p, err := existingProject(name); err == nil {
+			t.Errorf("existingProject(%q) = %s, want error", name, p)
+		}
+	}
+}
+
+func TestFindBuildOutputDirs(t *testing.T) {
+	root := makeTestProject(t, t.TempDir(), "robot")
+	addBuildPackage(t, root)
+	// Directories with output names outside the Gradle output locations are left alone.
+	for _, p := range []string{".git/build", "TeamCode/src/main/java/org/firstinspires/ftc/teamcode/captures", "docs/build"} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(p)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dirs, err := findBuildOutputDirs(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range dirs {
+		rel, _ := filepath.Rel(root, d)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	sort.Strings(got)
+	want := []string{".gradle", "TeamCode/build"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("findBuildOutputDirs = %v, want %v", got, want)
+	}
+}
+
+func TestCleanProject(t *testing.T) {
+	root := makeTestProject(t, t.TempDir(), "robot")
+	addBuildPackage(t, root)
+
+	freed, err := cleanProject("robot", root, true)
+	if err != nil || freed != int64(len("apk")+len("cache")) {
+		t.Fatalf("dry run = %d, %v", freed, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".gradle")); err != nil {
+		t.Fatal("dry run removed files")
+	}
+
+	if _, err := cleanProject("robot", root, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{".gradle", "TeamCode/build"} {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(p))); err == nil {
+			t.Errorf("%s not removed", p)
+		}
+	}
+	for _, p := range []string{"local.properties", "build.gradle", "TeamCode/src/main/java/org/firstinspires/ftc/teamcode/build/Lift.java"} {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(p))); err != nil {
+			t.Errorf("%s should be kept", p)
+		}
+	}
+}
+
+func TestProjectUsage(t *testing.T) {
+	dir := t.TempDir()
+	a := makeTestProject(t, dir, "a")
+	b := makeTestProject(t, dir, "b")
+
+	usage := projectUsage([]string{a, b})
+	for _, p := range []string{a, b} {
+		u := usage[p]
+		if u.Err != nil || u.Build != 8 || u.Total <= u.Build {
+			t.Errorf("usage[%s] = %+v", p, u)
+		}
+	}
+}
+
+func TestHumanSize(t *testing.T) {
+	tests := map[int64]string{0: "0 B", 1023: "1023 B", 1536: "1.5 KB", 5 << 30: "5.0 GB"}
+	for n, want := range tests {
+		if got := humanSize(n); got != want {
+			t.Errorf("humanSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// RemoteURL is a parsed git remote.
+type RemoteURL struct {
+	// Kind is one of "https", "ssh" (ssh://...) or "scp" (git@host:owner/repo).
+	Kind  string
+	User  string
+	Host  string
+	Port  string
+	Owner string
+	Repo  string
+}
+
+// String formats the remote in its canonical form for its kind.
+func (r RemoteURL) String() string {
+	p := r.Owner + "/" + r.Repo
+	switch r.Kind {
+	case "ssh":
+		host := r.Host
+		if r.Port != "" {
+			host += ":" + r.Port
+		}
+		return fmt.Sprintf("ssh://%s@%s/%s", r.User, host, p)
+	case "scp":
+		return fmt.Sprintf("%s@%s:%s", r.User, r.Host, p)
+	default:
+		return fmt.Sprintf("https://%s/%s", r.Host, p)
+	}
+}
+
+var (
+	scpRemoteRe       = regexp.MustCompile(`^([\w.\-]+)@([\w.\-]+):/?(.+)$`)
+	scpSlashRemoteRe  = regexp.MustCompile(`^([\w.\-]+)@([\w.\-]+\.[a-zA-Z]{2,})/(.+)$`)
+	shorthandRemoteRe = regexp.MustCompile(`^[\w.\-]+/[\w.\-]+$`)
+	hostPathRemoteRe  = regexp.MustCompile(`^([\w\-]+\.)+[a-zA-Z]{2,}/.+$`)
+)
+
+// ParseRemoteURL accepts https, ssh://, scp-style (git@host:owner/repo) and owner/repo
+// shorthand (assumed to be on GitHub) and returns the parsed remote. Common mistakes such as
+// git@github.com/owner/repo are corrected and reported in the returned warnings.
+func ParseRemoteURL(input string) (RemoteURL, []string, error) {
+	var warnings []string
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return RemoteURL{}, nil, errors.New("empty remote URL")
+	}
+	if strings.ContainsAny(s, " \t") {
+		return RemoteURL{}, nil, fmt.Errorf("remote URL %q contains spaces", input)
+	}
+
+	var r RemoteURL
+	var p string
+	switch {
+	case strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://"):
+		if strings.HasPrefix(s, "http://") {
+			warnings = append(warnings, "using https:// instead of http://")
+			s = "https://" + strings.TrimPrefix(s, "http://")
+		}
+		// https://github.com:owner/repo -> https://github.com/owner/repo
+		rest, fixed := fixHostColon(strings.TrimPrefix(s, "https://"))
+		if fixed {
+			warnings = append(warnings, "replaced ':' after the host with '/'")
+		}
+		u, err := url.Parse("https://" + rest)
+		if err != nil {
+			return RemoteURL{}, nil, err
+		}
+		r = RemoteURL{Kind: "https", Host: u.Host}
+		p = u.Path
+		if u.User != nil {
+			warnings = append(warnings, "removed credentials from the URL; use 'ftc-helper auth login' instead")
+		}
+	case strings.HasPrefix(s, "ssh://"):
+		// ssh://git@github.com:owner/repo -> ssh://git@github.com/owner/repo
+		rest, fixed := fixHostColon(strings.TrimPrefix(s, "ssh://"))
+		if fixed {
+			warnings = append(warnings, "replaced ':' after the host with '/'")
+		}
+		u, err := url.Parse("ssh://" + rest)
+		if err != nil {
+			return RemoteURL{}, nil, err
+		}
+		r = RemoteURL{Kind: "ssh", User: "git", Host: u.Hostname(), Port: u.Port()}
+		if u.User != nil && u.User.Username() != "" {
+			r.User = u.User.Username()
+		}
+		p = u.Path
+	case scpRemoteRe.MatchString(s):
+		m := scpRemoteRe.FindStringSubmatch(s)
+		r = RemoteURL{Kind: "scp", User: m[1], Host: m[2]}
+		p = m[3]
+	case scpSlashRemoteRe.MatchString(s):
+		// git@github.com/owner/repo is a common typo for git@github.com:owner/repo
+		m := scpSlashRemoteRe.FindStringSubmatch(s)
+		warnings = append(warnings, fmt.Sprintf("%s@%s/ should be %s@%s: (colon, not slash)", m[1], m[2], m[1], m[2]))
+		r = RemoteURL{Kind: "scp", User: m[1], Host: m[2]}
+		p = m[3]
+	case hostPathRemoteRe.MatchString(s):
+		u, err := url.Parse("https://" + s)
+		if err != nil {
+			return RemoteURL{}, nil, err
+		}
+		r = RemoteURL{Kind: "https", Host: u.Host}
+		p = u.Path
+	case shorthandRemoteRe.MatchString(s):
+		r = RemoteURL{Kind: "https", Host: "github.com"}
+		p = s
+	default:
+		return RemoteURL{}, nil, fmt.Errorf("unrecognized remote URL %q", input)
+	}
+
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[len(parts)-1] == "" {
+		return RemoteURL{}, nil, fmt.Errorf("remote URL %q must include owner and repository", input)
+	}
+	r.Owner = strings.Join(parts[:len(parts)-1], "/")
+	r.Repo = parts[len(parts)-1]
+	if !strings.HasSuffix(r.Repo, ".git") {
+		r.Repo += ".git"
+	}
+	if r.Host == "" {
+		return RemoteURL{}, nil, fmt.Errorf("remote URL %q has no host", input)
+	}
+	return r, warnings, nil
+}
+
+// fixHostColon rewrites "host:owner/repo" (scp syntax used inside a URL) to "host/owner/repo".
+// rest is the URL without its scheme; a numeric port after the colon is left alone.
+func fixHostColon(rest string) (string, bool) {
+	start := 0
+	if at := strings.Index(rest, "@"); at >= 0 && (strings.Index(rest, "/") == -1 || at < strings.Index(rest, "/")) {
+		start = at + 1
+	}
+	hostAndPath := rest[start:]
+	i := strings.Index(hostAndPath, ":")
+	if i == -1 {
+		return rest, false
+	}
+	if slash := strings.Index(hostAndPath, "/"); slash != -1 && slash < i {
+		return rest, false
+	}
+	if isPort(hostAndPath[i+1:]) {
+		return rest, false
+	}
+	return rest[:start] + hostAndPath[:i] + "/" + hostAndPath[i+1:], true
+}
+
+// isPort reports whether s starts with a port number followed by '/' or the end of the string.
+func isPort(s string) bool {
+	i := strings.Index(s, "/")
+	if i == -1 {
+		i = len(s)
+	}
+	if i == 0 {
+		return false
+	}
+	for _, c := range s[:i] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeRemote parses a user-supplied remote, prints any corrections and returns the URL to use.
+func normalizeRemote(input string) (string, error) {
+	r, warnings, err := ParseRemoteURL(input)
+	if err != nil {
+		return "", err
+	}
+	for _, w := range warnings {
+		fmt.Println("Warning:", w)
+	}
+	return r.String(), nil
+}
+
+// checkRemoteReachable runs `git ls-remote` against the URL so typos are caught before the remote is added.
+func checkRemoteReachable(remote string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", remote)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("remote %s is not reachable: %s", remote, msg)
+	}
+	return nil
+}
+
+// setRemote points origin at remote in dir, adding it if it does not exist yet.
+func setRemote(dir, remote string) error {
+	action := "add"
+	check := exec.Command("git", "remote", "get-url", "origin")
+	check.Dir = dir
+	if check.Run() == nil {
+		action = "set-url"
+	}
+
+	cmdGit := exec.Command("git", "remote", action, "origin", remote)
+	cmdGit.Dir = dir
+	if out, err := cmdGit.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// remote: view or change the git remote of an existing project
+var remoteCmd = &cobra.Command{
+	Use:   "remote",
+	Short: "Show or change a project's git remote",
+}
+
+var remoteSetCmd = &cobra.Command{
+	Use:   "set [project_name] [url]",
+	Short: "Set the origin remote of a project's TeamCode repository",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName := args[0]
+		skipCheck, _ := cmd.Flags().GetBool("skip-check")
+		teamCodePath := teamCodeDir(projectName)
+
+		if _, err := os.Stat(teamCodePath); os.IsNotExist(err) {
+			fmt.Println("Project not found or TeamCode directory does not exist.")
+			return
+		}
+
+		remote, err := normalizeRemote(args[1])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		if !skipCheck {
+			fmt.Println("Checking remote...")
+			if err := checkRemoteReachable(remote); err != nil {
+				fmt.Println("Error:", err)
+				fmt.Println("Use --skip-check to set it anyway.")
+				return
+			}
+		}
+
+		if err := setRemote(teamCodePath, remote); err != nil {
+			fmt.Println("Error setting git remote:", err)
+			return
+		}
+		fmt.Printf("Remote for '%s' set to %s\n", projectName, remote)
+	},
+}
+
+var remoteShowCmd = &cobra.Command{
+	Use:   "show [project_name]",
+	Short: "Show the remotes of a project's TeamCode repository",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		teamCodePath := teamCodeDir(args[0])
+
+		if _, err := os.Stat(teamCodePath); os.IsNotExist(err) {
+			fmt.Println("Project not found or TeamCode directory does not exist.")
+			return
+		}
+
+		out, err := exec.Command("git", "-C", teamCodePath, "remote", "-v").Output()
+		if err != nil {
+			fmt.Println("Error reading git remotes:", err)
+			return
+		}
+		if strings.TrimSpace(string(out)) == "" {
+			fmt.Println("No remotes configured.")
+			return
+		}
+		fmt.Print(string(out))
+	},
+}
+
+func init() {
+	remoteSetCmd.Flags().Bool("skip-check", false, "Do not check that the remote is reachable")
+	remoteCmd.AddCommand(remoteSetCmd)
+	remoteCmd.AddCommand(remoteShowCmd)
+}
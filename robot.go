@@ -0,0 +1,500 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Control Hubs and Driver Hubs serve adb over Wi-Fi at this address on their own access point.
+const (
+	defaultRobotIP   = "192.168.43.1"
+	defaultRobotPort = 5555
+)
+
+// Robot is a saved robot from the "robots" config section.
+type Robot struct {
+	Name string
+	SSID string
+	IP   string
+	Port int
+}
+
+// addr is the adb serial of the robot over Wi-Fi.
+func (r Robot) addr() string {
+	return fmt.Sprintf("%s:%d", r.IP, r.Port)
+}
+
+// configuredRobots returns the saved robots, sorted by name. Viper lower-cases keys, so names are case-insensitive.
+func configuredRobots() []Robot {
+	var list []Robot
+	for name := range viper.GetStringMap("robots") {
+		fields := viper.GetStringMapString("robots." + name)
+		r := Robot{Name: name, SSID: fields["ssid"], IP: valueOr(fields["ip"], defaultRobotIP), Port: defaultRobotPort}
+		if p, err := strconv.Atoi(fields["port"]); err == nil && p > 0 {
+			r.Port = p
+		}
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// findRobot looks up a saved robot by name.
+func findRobot(name string) (Robot, bool) {
+	for _, r := range configuredRobots() {
+		if r.Name == strings.ToLower(name) {
+			return r, true
+		}
+	}
+	return Robot{}, false
+}
+
+// selectedRobot returns the named robot, or the active one ("robot" key), or the default Control Hub address
+// when no robots are saved.
+func selectedRobot(name string) (Robot, error) {
+	if name == "" {
+		name = viper.GetString("robot")
+	}
+	if name == "" {
+		if len(configuredRobots()) == 0 {
+			return Robot{Name: "control hub", IP: defaultRobotIP, Port: defaultRobotPort}, nil
+		}
+		return Robot{}, fmt.Errorf("no active robot; pick one with 'ftc-helper robot use <name>'")
+	}
+	r, ok := findRobot(name)
+	if !ok {
+		return Robot{}, fmt.Errorf("robot %q is not configured; add it with 'ftc-helper robot add'", name)
+	}
+	return r, nil
+}
+
+// attachedDevices returns the serials of the devices adb lists as ready.
+func attachedDevices(adb ADB) ([]string, error) {
+	out, err := adb.Run("devices")
+	if err != nil {
+		return nil, err
+	}
+	var serials []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if f := strings.Fields(line); len(f) == 2 && f[1] == "device" {
+			serials = append(serials, f[0])
+		}
+	}
+	return serials, nil
+}
+
+// robotSerial picks the adb serial for r: its Wi-Fi address when adb is connected to it, otherwise the
+// only attached device, which at the bench is usually a Control Hub on USB. ANDROID_SERIAL wins over
+// both, as it does for adb itself.
+func robotSerial(adb ADB, r Robot) (string, error) {
+	if s := os.Getenv("ANDROID_SERIAL"); s != "" {
+		return s, nil
+	}
+	devices, err := attachedDevices(adb)
+	if err != nil {
+		return "", err
+	}
+	for _, d := range devices {
+		if d == r.addr() {
+			return d, nil
+		}
+	}
+	switch len(devices) {
+	case 0:
+		return "", fmt.Errorf("%s (%s) is not connected and no device is attached over USB; run 'ftc-helper robot connect' or plug in a USB cable", r.Name, r.addr())
+	case 1:
+		return devices[0], nil
+	default:
+		return "", fmt.Errorf("%s (%s) is not connected and several devices are attached (%s); set ANDROID_SERIAL to pick one", r.Name, r.addr(), strings.Join(devices, ", "))
+	}
+}
+
+// robotDevice returns the named (or active) robot, the adb serial it is reachable on and an adb runner
+// talking to it.
+func robotDevice(name string) (Robot, string, ADB, error) {
+	r, err := selectedRobot(name)
+	if err != nil {
+		return r, "", nil, err
+	}
+	adb, err := newADB("")
+	if err != nil {
+		return r, "", nil, err
+	}
+	serial, err := robotSerial(adb, r)
+	if err != nil {
+		return r, "", nil, err
+	}
+	adb, err = newADB(serial)
+	return r, serial, adb, err
+}
+
+// WiFi joins and reports the laptop's Wi-Fi network. It is an interface so tests can fake the network.
+type WiFi interface {
+	CurrentSSID() (string, error)
+	Connect(ssid string) error
+}
+
+// systemWiFi uses the OS network tools: netsh on Windows, networksetup on macOS and nmcli on Linux.
+// Connecting relies on the network already being known to the OS (joined once with its password).
+type systemWiFi struct{}
+
+var (
+	netshSSID = regexp.MustCompile(`(?m)^\s*SSID\s*:\s*(.+?)\s*$`)
+	macSSID   = regexp.MustCompile(`Current Wi-Fi Network:\s*(.+?)\s*$`)
+)
+
+func (systemWiFi) CurrentSSID() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		out, err := exec.Command("netsh", "wlan", "show", "interfaces").Output()
+		if err != nil {
+			return "", err
+		}
+		if m := netshSSID.FindSubmatch(out); m != nil {
+			return string(m[1]), nil
+		}
+	case "darwin":
+		out, err := exec.Command("networksetup", "-getairportnetwork", "en0").Output()
+		if err != nil {
+			return "", err
+		}
+		if m := macSSID.FindSubmatch(out); m != nil {
+			return string(m[1]), nil
+		}
+	default:
+		out, err := exec.Command("nmcli", "-t", "-f", "active,ssid", "dev", "wifi").Output()
+		if err != nil {
+			return "", err
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if strings.HasPrefix(line, "yes:") {
+				return strings.TrimPrefix(line, "yes:"), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("not connected to Wi-Fi")
+}
+
+func (systemWiFi) Connect(ssid string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("netsh", "wlan", "connect", "name="+ssid)
+	case "darwin":
+		cmd = exec.Command("networksetup", "-setairportnetwork", "en0", ssid)
+	default:
+		cmd = exec.Command("nmcli", "dev", "wifi", "connect", ssid)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("joining %s: %v: %s", ssid, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// newWiFi returns the Wi-Fi implementation used by commands. A variable so tests can substitute a fake.
+var newWiFi = func() WiFi { return systemWiFi{} }
+
+// robotConnector connects to robots with retries. sleep is replaced in tests.
+type robotConnector struct {
+	adb      ADB
+	wifi     WiFi
+	attempts int
+	wait     time.Duration
+	sleep    func(time.Duration)
+	log      func(format string, args ...interface{})
+}
+
+func newRobotConnector(adb ADB) *robotConnector {
+	return &robotConnector{
+		adb:      adb,
+		wifi:     newWiFi(),
+		attempts: 3,
+		wait:     2 * time.Second,
+		sleep:    time.Sleep,
+		log:      func(format string, args ...interface{}) { fmt.Printf(format, args...) },
+	}
+}
+
+// connect joins the robot's Wi-Fi network if needed, then runs adb connect until it succeeds.
+// adb connect exits 0 even when it fails, so its output is checked instead.
+func (c *robotConnector) connect(r Robot) error {
+	if r.SSID != "" {
+		current, err := c.wifi.CurrentSSID()
+		if err != nil || current != r.SSID {
+			c.log("Joining Wi-Fi network %s...\n", r.SSID)
+			if err := c.wifi.Connect(r.SSID); err != nil {
+				return err
+			}
+			// The robot's access point takes a moment to hand out an address.
+			c.sleep(c.wait)
+		}
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= c.attempts; attempt++ {
+		out, err := c.adb.Run("connect", r.addr())
+		text := strings.TrimSpace(string(out))
+		switch {
+		case err != nil:
+			lastErr = err
+		case strings.HasPrefix(text, "connected to") || strings.HasPrefix(text, "already connected to"):
+			return nil
+		default:
+			lastErr = fmt.Errorf("%s", valueOr(text, "adb connect failed"))
+		}
+		if attempt < c.attempts {
+			c.log("Attempt %d/%d failed (%v), retrying...\n", attempt, c.attempts, lastErr)
+			c.sleep(c.wait * time.Duration(attempt))
+		}
+	}
+	return fmt.Errorf("could not connect to %s: %w", r.addr(), lastErr)
+}
+
+// RobotStatus is what `robot status` reports about a connected device.
+type RobotStatus struct {
+	Model      string
+	RCVersion  string // Robot Controller app version, empty if not installed
+	DSVersion  string // Driver Station app version, empty if not installed
+	Battery    string // percent, empty when the device has no battery (Control Hub)
+	AndroidVer string
+}
+
+// robotControllerPackage and driverStationPackage are the FTC app package names.
+const (
+	robotControllerPackage = "com.qualcomm.ftcrobotcontroller"
+	driverStationPackage   = "com.qualcomm.ftcdriverstation"
+)
+
+var (
+	versionNameLine = regexp.MustCompile(`versionName=(\S+)`)
+	batteryLevel    = regexp.MustCompile(`(?m)^\s*level:\s*(\d+)`)
+	batteryPresent  = regexp.MustCompile(`(?m)^\s*present:\s*false`)
+)
+
+// packageVersion returns the installed version of an app, or "" if it isn't installed.
+func packageVersion(adb ADB, pkg string) (string, error) {
+	out, err := adb.Run("shell", "dumpsys", "package", pkg)
+	if err != nil {
+		return "", err
+	}
+	if m := versionNameLine.FindSubmatch(out); m != nil {
+		return string(m[1]), nil
+	}
+	return "", nil
+}
+
+// robotStatus queries the device's model, FTC app versions and battery.
+func robotStatus(adb ADB) (RobotStatus, error) {
+	var s RobotStatus
+	out, err := adb.Run("shell", "getprop", "ro.product.model")
+	if err != nil {
+		return s, err
+	}
+	s.Model = strings.TrimSpace(string(out))
+	if out, err := adb.Run("shell", "getprop", "ro.build.version.release"); err == nil {
+		s.AndroidVer = strings.TrimSpace(string(out))
+	}
+	if s.RCVersion, err = packageVersion(adb, robotControllerPackage); err != nil {
+		return s, err
+	}
+	if s.DSVersion, err = packageVersion(adb, driverStationPackage); err != nil {
+		return s, err
+	}
+	if out, err := adb.Run("shell", "dumpsys", "battery"); err == nil && !batteryPresent.Match(out) {
+		if m := batteryLevel.FindSubmatch(out); m != nil {
+			s.Battery = string(m[1]) + "%"
+		}
+	}
+	return s, nil
+}
+
+// robot: connect to robots over Wi-Fi with adb
+var robotCmd = &cobra.Command{
+	Use:   "robot",
+	Short: "Connect to and manage robots (Control Hub / Driver Hub) over Wi-Fi",
+}
+
+var robotAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Save a robot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.ToLower(args[0])
+		ssid, _ := cmd.Flags().GetString("ssid")
+		ip, _ := cmd.Flags().GetString("ip")
+		port, _ := cmd.Flags().GetInt("port")
+		use, _ := cmd.Flags().GetBool("use")
+
+		if strings.ContainsAny(name, ". ") {
+			fmt.Println("Robot names cannot contain dots or spaces.")
+			return
+		}
+		value := map[string]interface{}{"ip": ip, "port": port}
+		if ssid != "" {
+			value["ssid"] = ssid
+		}
+		if _, err := saveConfigValue("robots."+name, value); err != nil {
+			fmt.Println("Error saving config:", err)
+			return
+		}
+		fmt.Printf("Added robot %s (%s:%d)\n", name, ip, port)
+
+		if use || viper.GetString("robot") == "" {
+			if _, err := saveConfigValue("robot", name); err != nil {
+				fmt.Println("Error saving config:", err)
+				return
+			}
+			fmt.Println("Active robot:", name)
+		}
+	},
+}
+
+var robotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved robots",
+	Run: func(cmd *cobra.Command, args []string) {
+		list := configuredRobots()
+		if len(list) == 0 {
+			fmt.Println("No robots saved. Add one with 'ftc-helper robot add <name> --ssid <wifi>'.")
+			return
+		}
+		active := strings.ToLower(viper.GetString("robot"))
+		for _, r := range list {
+			mark := " "
+			if r.Name == active {
+				mark = "*"
+			}
+			fmt.Printf("%s %-16s %-22s %s\n", mark, r.Name, r.addr(), valueOr(r.SSID, "-"))
+		}
+	},
+}
+
+var robotUseCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Set the active robot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, ok := findRobot(args[0])
+		if !ok {
+			fmt.Printf("Robot '%s' is not configured.\n", args[0])
+			return
+		}
+		if _, err := saveConfigValue("robot", r.Name); err != nil {
+			fmt.Println("Error saving config:", err)
+			return
+		}
+		fmt.Println("Active robot:", r.Name)
+	},
+}
+
+var robotConnectCmd = &cobra.Command{
+	Use:   "connect [name]",
+	Short: "Join the robot's Wi-Fi and connect adb",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var name string
+		if len(args) == 1 {
+			name = args[0]
+		}
+		r, err := selectedRobot(name)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		adb, err := newADB("")
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		fmt.Printf("Connecting to %s (%s)...\n", r.Name, r.addr())
+		if err := newRobotConnector(adb).connect(r); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Println("Connected.")
+		if name != "" && viper.GetString("robot") != r.Name && len(configuredRobots()) > 0 {
+			if _, err := saveConfigValue("robot", r.Name); err == nil {
+				fmt.Println("Active robot:", r.Name)
+			}
+		}
+	},
+}
+
+var robotDisconnectCmd = &cobra.Command{
+	Use:   "disconnect [name]",
+	Short: "Disconnect adb from the robot",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var name string
+		if len(args) == 1 {
+			name = args[0]
+		}
+		r, err := selectedRobot(name)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		adb, err := newADB("")
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		if _, err := adb.Run("disconnect", r.addr()); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Printf("Disconnected from %s.\n", r.Name)
+	},
+}
+
+var robotStatusCmd = &cobra.Command{
+	Use:   "status [name]",
+	Short: "Show the connected robot's model, FTC app versions and battery",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var name string
+		if len(args) == 1 {
+			name = args[0]
+		}
+		r, serial, adb, err := robotDevice(name)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		s, err := robotStatus(adb)
+		if err != nil {
+			fmt.Printf("%s (%s) is not responding: %v\n", r.Name, serial, err)
+			return
+		}
+		fmt.Printf("Robot:            %s (%s)\n", r.Name, serial)
+		if r.SSID != "" {
+			ssid, _ := newWiFi().CurrentSSID()
+			fmt.Printf("Wi-Fi:            %s (laptop on %s)\n", r.SSID, valueOr(ssid, "unknown"))
+		}
+		fmt.Printf("Model:            %s (Android %s)\n", s.Model, valueOr(s.AndroidVer, "?"))
+		fmt.Printf("Robot Controller: %s\n", valueOr(s.RCVersion, "not installed"))
+		if s.DSVersion != "" {
+			fmt.Printf("Driver Station:   %s\n", s.DSVersion)
+		}
+		fmt.Printf("Battery:          %s\n", valueOr(s.Battery, "n/a"))
+	},
+}
+
+func init() {
+	robotAddCmd.Flags().String("ssid", "", "Wi-Fi network of the robot, e.g. 12345-RC")
+	robotAddCmd.Flags().String("ip", defaultRobotIP, "Robot IP address")
+	robotAddCmd.Flags().Int("port", defaultRobotPort, "adb port")
+	robotAddCmd.Flags().Bool("use", false, "Make this the active robot")
+	robotCmd.AddCommand(robotAddCmd, robotListCmd, robotUseCmd, robotConnectCmd, robotDisconnectCmd, robotStatusCmd)
+}
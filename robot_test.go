@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type fakeWiFi struct {
+	ssid   string
+	joined []string
+	err    error
+}
+
+func (w *fakeWiFi) CurrentSSID() (string, error) { return w.ssid, nil }
+
+func (w *fakeWiFi) Connect(ssid string) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.joined = append(w.joined, ssid)
+	w.ssid = ssid
+	return nil
+}
+
+// sequenceADB answers `adb connect` with the given outputs in turn.
+type sequenceADB struct {
+	outputs []string
+	calls   int
+}
+
+func (a *sequenceADB) Run(args ...string) ([]byte, error) {
+	if args[0] != "connect" {
+		return nil, fmt.Errorf("unexpected command %v", args)
+	}
+	out := a.outputs[a.calls]
+	a.calls++
+	return []byte(out), nil
+}
+
+func testConnector(adb ADB, wifi WiFi) *robotConnector {
+	return &robotConnector{
+		adb:      adb,
+		wifi:     wifi,
+		attempts: 3,
+		wait:     time.Second,
+		sleep:    func(time.Duration) {},
+		log:      func(string, ...interface{}) {},
+	}
+}
+
+func TestRobotConnect(t *testing.T) {
+	r := Robot{Name: "comp", SSID: "12345-RC", IP: defaultRobotIP, Port: defaultRobotPort}
+
+	tests := []struct {
+		name      string
+		outputs   []string
+		ssid      string
+		wantErr   bool
+		wantCalls int
+		wantJoin  bool
+	}{
+		{"first try", []string{"connected to 192.168.43.1:5555"}, "12345-RC", false, 1, false},
+		{"already connected", []string{"already connected to 192.168.43.1:5555"}, "12345-RC", false, 1, false},
+		{"joins wifi", []string{"connected to 192.168.43.1:5555"}, "school", false, 1, true},
+		{"retries", []string{"failed to connect to '192.168.43.1:5555': Connection refused", "connected to 192.168.43.1:5555"}, "12345-RC", false, 2, false},
+		{"gives up", []string{"cannot connect", "cannot connect", "cannot connect"}, "12345-RC", true, 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adb := &sequenceADB{outputs: tt.outputs}
+			wifi := &fakeWiFi{ssid: tt.ssid}
+			err := testConnector(adb, wifi).connect(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("connect error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if adb.calls != tt.wantCalls {
+				t.Errorf("adb connect called %d times, want %d", adb.calls, tt.wantCalls)
+			}
+			if joined := len(wifi.joined) > 0; joined != tt.wantJoin {
+				t.Errorf("joined wifi = %v, want %v", wifi.joined, tt.wantJoin)
+			}
+		})
+	}
+}
+
+func TestRobotConnect_WiFiFailure(t *testing.T) {
+	adb := &sequenceADB{}
+	wifi := &fakeWiFi{ssid: "school", err: fmt.Errorf("network not found")}
+	err := testConnector(adb, wifi).connect(Robot{SSID: "12345-RC", IP: defaultRobotIP, Port: defaultRobotPort})
+	if err == nil || !strings.Contains(err.Error(), "network not found") {
+		t.Errorf("connect error = %v, want wifi error", err)
+	}
+	if adb.calls != 0 {
+		t.Errorf("adb connect called %d times after wifi failure", adb.calls)
+	}
+}
+
+func TestRobotStatus(t *testing.T) {
+	adb := &fakeADB{responses: map[string]string{
+		"shell getprop ro.product.model":                  "Control Hub\n",
+		"shell getprop ro.build.version.release":          "7.1.2\n",
+		"shell dumpsys package " + robotControllerPackage: "Packages:\n    versionCode=57 minSdk=24\n    versionName=10.1\n",
+		"shell dumpsys package " + driverStationPackage:   "",
+		"shell dumpsys battery":                           "Current Battery Service state:\n  present: true\n  level: 87\n",
+	}}
+	s, err := robotStatus(adb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RobotStatus{Model: "Control Hub", RCVersion: "10.1", Battery: "87%", AndroidVer: "7.1.2"}
+	if s != want {
+		t.Errorf("robotStatus = %+v, want %+v", s, want)
+	}
+
+	adb.responses["shell dumpsys battery"] = "  present: false\n  level: 0\n"
+	if s, _ := robotStatus(adb); s.Battery != "" {
+		t.Errorf("Battery = %q for a device without battery", s.Battery)
+	}
+}
+
+func TestSelectedRobot(t *testing.T) {
+	defer viper.Set("robots", nil)
+	defer viper.Set("robot", "")
+
+	viper.Set("robots", nil)
+	viper.Set("robot", "")
+	if r, err := selectedRobot(""); err != nil || r.addr() != "192.168.43.1:5555" {
+		t.Errorf("no robots: selectedRobot = %+v, %v; want default Control Hub", r, err)
+	}
+
+	viper.Set("robots", map[string]interface{}{
+		"comp":     map[string]interface{}{"ssid": "12345-RC", "ip": "192.168.43.1", "port": 5555},
+		"practice": map[string]interface{}{"ip": "192.168.49.1", "port": "5556"},
+	})
+	if _, err := selectedRobot(""); err == nil {
+		t.Error("expected error with saved robots and no active one")
+	}
+	viper.Set("robot", "practice")
+	r, err := selectedRobot("")
+	if err != nil || r.addr() != "192.168.49.1:5556" {
+		t.Errorf("active robot = %+v, %v", r, err)
+	}
+	r, err = selectedRobot("Comp")
+	if err != nil || r.SSID != "12345-RC" {
+		t.Errorf("named robot = %+v, %v", r, err)
+	}
+	if _, err := selectedRobot("missing"); err == nil {
+		t.Error("expected error for unknown robot")
+	}
+}
+
+func TestRobotSerial(t *testing.T) {
+	hub := Robot{Name: "comp", IP: defaultRobotIP, Port: defaultRobotPort}
+	tests := []struct {
+		name    string
+		devices string
+		want    string
+		wantErr string
+	}{
+		{"wifi connected", "List of devices attached\n192.168.43.1:5555\tdevice\nR3CT1234\tdevice\n", "192.168.43.1:5555", ""},
+		{"usb only", "List of devices attached\nR3CT1234\tdevice\n", "R3CT1234", ""},
+		{"unauthorized usb", "List of devices attached\nR3CT1234\tunauthorized\n", "", "no device is attached"},
+		{"nothing", "List of devices attached\n\n", "", "no device is attached"},
+		{"several", "List of devices attached\nR3CT1234\tdevice\nemulator-5554\tdevice\n", "", "ANDROID_SERIAL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ANDROID_SERIAL", "")
+			adb := &fakeADB{responses: map[string]string{"devices": tt.devices}}
+			got, err := robotSerial(adb, hub)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("robotSerial = %q, %v, want error containing %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("robotSerial = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	t.Setenv("ANDROID_SERIAL", "R3CT9999")
+	if got, err := robotSerial(&fakeADB{}, hub); err != nil || got != "R3CT9999" {
+		t.Errorf("robotSerial with ANDROID_SERIAL = %q, %v", got, err)
+	}
+}
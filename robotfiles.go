@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// robotPaths are shortcuts for the places FTC apps keep files on the robot. "matchlogs:" is the
+// directory itself and "matchlogs:Match-3.txt" a file in it.
+var robotPaths = map[string]string{
+	"first":       robotConfigDir,
+	"matchlogs":   robotConfigDir + "/matchlogs",
+	"calibration": robotConfigDir + "/teamwebcamcalibrations.xml",
+	"tflite":      robotConfigDir + "/tflitemodels",
+	"settings":    robotConfigDir + "/settings",
+}
+
+// resolveRemotePath expands a shortcut such as "tflite:model.tflite". Relative paths are relative to /sdcard/FIRST.
+func resolveRemotePath(arg string) string {
+	if i := strings.Index(arg, ":"); i > 0 {
+		if base, ok := robotPaths[strings.ToLower(arg[:i])]; ok {
+			return path.Join(base, arg[i+1:])
+		}
+	}
+	if strings.HasPrefix(arg, "/") {
+		return path.Clean(arg)
+	}
+	return path.Join(robotConfigDir, arg)
+}
+
+// shellQuote quotes s for the device shell that runs `adb shell` commands.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// RemoteFile is a regular file on the robot.
+type RemoteFile struct {
+	Path string
+	Size int64
+}
+
+// listRemote returns the files at p on the robot: p itself if it is a file, or every file below it.
+func listRemote(adb ADB, p string) ([]RemoteFile, error) {
+	out, err := adb.Run("shell", "find", shellQuote(p), "-type", "f", "-exec", "stat", "-c", "%s:%n", "{}", "+")
+	if err != nil {
+		return nil, err
+	}
+	var files []RemoteFile
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		size, name, ok := strings.Cut(strings.TrimSpace(line), ":")
+		n, err := strconv.ParseInt(size, 10, 64)
+		if !ok || err != nil {
+			// find reports errors such as "No such file or directory" on stdout through adb shell.
+			if strings.Contains(line, "No such file") {
+				return nil, fmt.Errorf("%s does not exist on the robot", p)
+			}
+			continue
+		}
+		files = append(files, RemoteFile{Path: name, Size: n})
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files found at %s on the robot", p)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// remoteChecksums returns the MD5 of each file on the robot. Android's toybox always has md5sum.
+func remoteChecksums(adb ADB, paths []string) (map[string]string, error) {
+	sums := map[string]string{}
+	const batch = 50
+	for i := 0; i < len(paths); i += batch {
+		args := []string{"shell", "md5sum"}
+		for _, p := range paths[i:min(i+batch, len(paths))] {
+			args = append(args, shellQuote(p))
+		}
+		out, err := adb.Run(args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if sum, name, ok := strings.Cut(strings.TrimSpace(line), "  "); ok {
+				sums[name] = sum
+			}
+		}
+	}
+	return sums, nil
+}
+
+func localChecksum(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Transfer is one file copied between the laptop and the robot.
+type Transfer struct {
+	Local  string
+	Remote string
+	Size   int64
+}
+
+// pullPlan maps the files at remote to local paths. A directory is copied into local under its own
+// name; a single file goes to local, or into it when local is an existing directory.
+func pullPlan(files []RemoteFile, remote, local string) []Transfer {
+	var plan []Transfer
+	single := len(files) == 1 && files[0].Path == remote
+	for _, f := range files {
+		var dst string
+		switch {
+		case single:
+			dst = local
+			if fi, err := os.Stat(local); err == nil && fi.IsDir() {
+				dst = filepath.Join(local, path.Base(remote))
+			}
+		default:
+			rel := strings.TrimPrefix(strings.TrimPrefix(f.Path, remote), "/")
+			dst = filepath.Join(local, path.Base(remote), filepath.FromSlash(rel))
+		}
+		plan = append(plan, Transfer{Local: dst, Remote: f.Path, Size: f.Size})
+	}
+	return plan
+}
+
+// pushPlan maps the files at local to paths below remote. remoteIsDir says whether remote is an
+// existing directory on the robot, in which case a single file keeps its name.
+func pushPlan(local, remote string, remoteIsDir bool) ([]Transfer, error) {
+	fi, err := os.Stat(local)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		dst := remote
+		if remoteIsDir {
+			dst = path.Join(remote, filepath.Base(local))
+		}
+		return []Transfer{{Local: local, Remote: dst, Size: fi.Size()}}, nil
+	}
+
+	var plan []Transfer
+	err = filepath.Walk(local, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(local, p)
+		if err != nil {
+			return err
+		}
+		plan = append(plan, Transfer{Local: p, Remote: path.Join(remote, filepath.Base(local), filepath.ToSlash(rel)), Size: info.Size()})
+		return nil
+	})
+	if len(plan) == 0 && err == nil {
+		err = fmt.Errorf("%s has no files", local)
+	}
+	return plan, err
+}
+
+// printTransfers lists a plan with sizes and a total.
+func printTransfers(w io.Writer, plan []Transfer, pushing bool) {
+	var total int64
+	for _, t := range plan {
+		from, to := t.Remote, t.Local
+		if pushing {
+			from, to = t.Local, t.Remote
+		}
+		fmt.Fprintf(w, "  %10s  %s -> %s\n", humanSize(t.Size), from, to)
+		total += t.Size
+	}
+	fmt.Fprintf(w, "%d file(s), %s\n", len(plan), humanSize(total))
+}
+
+// verifyTransfers compares the MD5 of each file on both sides and returns the ones that differ.
+func verifyTransfers(adb ADB, plan []Transfer) ([]Transfer, error) {
+	var remotes []string
+	for _, t := range plan {
+		remotes = append(remotes, t.Remote)
+	}
+	sums, err := remoteChecksums(adb, remotes)
+	if err != nil {
+		return nil, err
+	}
+	var bad []Transfer
+	for _, t := range plan {
+		local, err := localChecksum(t.Local)
+		if err != nil || sums[t.Remote] != local {
+			bad = append(bad, t)
+		}
+	}
+	return bad, nil
+}
+
+// copyFiles runs adb pull or push for every file in the plan, then verifies the copies.
+func copyFiles(adb ADB, plan []Transfer, pushing bool) error {
+	for _, t := range plan {
+		var err error
+		if pushing {
+			_, err = adb.Run("push", t.Local, t.Remote)
+		} else {
+			if err = os.MkdirAll(filepath.Dir(t.Local), 0755); err == nil {
+				_, err = adb.Run("pull", t.Remote, t.Local)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	bad, err := verifyTransfers(adb, plan)
+	if err != nil {
+		return fmt.Errorf("verifying: %w", err)
+	}
+	if len(bad) > 0 {
+		var names []string
+		for _, t := range bad {
+			names = append(names, t.Remote)
+		}
+		return fmt.Errorf("checksum mismatch after copy: %s", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// pngSignature starts every PNG file.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// takeScreenshot captures the robot's screen as PNG.
+func takeScreenshot(adb ADB) ([]byte, error) {
+	out, err := adb.Run("exec-out", "screencap", "-p")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(out, pngSignature) {
+		return nil, fmt.Errorf("screencap did not return a PNG image")
+	}
+	return out, nil
+}
+
+func robotPathsHelp() string {
+	var names []string
+	for k := range robotPaths {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString("Remote paths are relative to " + robotConfigDir + " unless absolute. Shortcuts:\n")
+	for _, k := range names {
+		fmt.Fprintf(&b, "  %-13s %s\n", k+":", robotPaths[k])
+	}
+	return b.String()
+}
+
+var robotPullCmd = &cobra.Command{
+	Use:   "pull [remote] [local]",
+	Short: "Copy files from the robot, e.g. 'robot pull matchlogs:'",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		robotName, _ := cmd.Flags().GetString("robot")
+		remote := resolveRemotePath(args[0])
+		local := "."
+		if len(args) == 2 {
+			local = args[1]
+		}
+		_, _, adb, err := robotDevice(robotName)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		files, err := listRemote(adb, remote)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		plan := pullPlan(files, remote, local)
+		printTransfers(os.Stdout, plan, false)
+		if err := copyFiles(adb, plan, false); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Println("Copied and verified.")
+	},
+}
+
+var robotPushCmd = &cobra.Command{
+	Use:   "push [local] [remote]",
+	Short: "Copy files to the robot, e.g. 'robot push model.tflite tflite:'",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		robotName, _ := cmd.Flags().GetString("robot")
+		remote := resolveRemotePath(args[1])
+		_, _, adb, err := robotDevice(robotName)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		out, _ := adb.Run("shell", "[ -d "+shellQuote(remote)+" ] && echo dir")
+		isDir := strings.TrimSpace(string(out)) == "dir" || strings.HasSuffix(args[1], "/") || strings.HasSuffix(args[1], ":")
+		plan, err := pushPlan(args[0], remote, isDir)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		printTransfers(os.Stdout, plan, true)
+		if err := copyFiles(adb, plan, true); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Println("Copied and verified.")
+	},
+}
+
+var robotScreenshotCmd = &cobra.Command{
+	Use:   "screenshot [file.png]",
+	Short: "Save a screenshot of the robot's screen (Driver Hub or phone)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		robotName, _ := cmd.Flags().GetString("robot")
+		out := "screenshot-" + time.Now().Format("20060102-150405") + ".png"
+		if len(args) == 1 {
+			out = args[0]
+		}
+		_, _, adb, err := robotDevice(robotName)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		png, err := takeScreenshot(adb)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		if err := os.WriteFile(out, png, 0644); err != nil {
+			fmt.Println("Error writing screenshot:", err)
+			return
+		}
+		fmt.Printf("Saved %s (%s)\n", out, humanSize(int64(len(png))))
+	},
+}
+
+func init() {
+	robotPullCmd.Long = "Copies a file or directory from the robot and verifies the copy.\n\n" + robotPathsHelp()
+	robotPushCmd.Long = "Copies a file or directory to the robot and verifies the copy.\n\n" + robotPathsHelp()
+	for _, c := range []*cobra.Command{robotPullCmd, robotPushCmd, robotScreenshotCmd} {
+		c.Flags().String("robot", "", "Saved robot to use (default: the active robot)")
+		robotCmd.AddCommand(c)
+	}
+}
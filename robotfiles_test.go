@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveRemotePath(t *testing.T) {
+	tests := []struct {
+		arg, want string
+	}{
+		{"matchlogs:", "/sdcard/FIRST/matchlogs"},
+		{"MatchLogs:Match-3.txt", "/sdcard/FIRST/matchlogs/Match-3.txt"},
+		{"tflite:model.tflite", "/sdcard/FIRST/tflitemodels/model.tflite"},
+		{"calibration:", "/sdcard/FIRST/teamwebcamcalibrations.xml"},
+		{"paths/auto.json", "/sdcard/FIRST/paths/auto.json"},
+		{"/sdcard/Download/", "/sdcard/Download"},
+		{"unknown:x", "/sdcard/FIRST/unknown:x"},
+	}
+	for _, tt := range tests {
+		if got := resolveRemotePath(tt.arg); got != tt.want {
+			t.Errorf("resolveRemotePath(%q) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("it's here"); got != `'it'\''s here'` {
+		t.Errorf("shellQuote = %s", got)
+	}
+}
+
+func TestRobotPull(t *testing.T) {
+	adb := &fakeADB{files: map[string]string{
+		"/sdcard/FIRST/matchlogs/Match-1.txt":   "one",
+		"/sdcard/FIRST/matchlogs/q/Match-2.txt": "two!",
+		"/sdcard/FIRST/robot.xml":               "<Robot/>",
+	}}
+	local := t.TempDir()
+
+	files, err := listRemote(adb, "/sdcard/FIRST/matchlogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[1].Size != 4 {
+		t.Fatalf("listRemote = %+v", files)
+	}
+	plan := pullPlan(files, "/sdcard/FIRST/matchlogs", local)
+	if want := filepath.Join(local, "matchlogs", "q", "Match-2.txt"); plan[1].Local != want {
+		t.Errorf("pull destination = %s, want %s", plan[1].Local, want)
+	}
+	if err := copyFiles(adb, plan, false); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(plan[1].Local); string(b) != "two!" {
+		t.Errorf("pulled %q", b)
+	}
+
+	// A single file into an existing directory keeps its name.
+	files, _ = listRemote(adb, "/sdcard/FIRST/robot.xml")
+	plan = pullPlan(files, "/sdcard/FIRST/robot.xml", local)
+	if want := filepath.Join(local, "robot.xml"); len(plan) != 1 || plan[0].Local != want {
+		t.Errorf("single file plan = %+v", plan)
+	}
+
+	if _, err := listRemote(adb, "/sdcard/FIRST/missing"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("listRemote(missing) error = %v", err)
+	}
+}
+
+func TestRobotPush(t *testing.T) {
+	adb := &fakeADB{files: map[string]string{}}
+	dir := filepath.Join(t.TempDir(), "paths")
+	os.MkdirAll(filepath.Join(dir, "red"), 0755)
+	os.WriteFile(filepath.Join(dir, "red", "auto.json"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(dir, "blue.json"), []byte("[]"), 0644)
+
+	plan, err := pushPlan(dir, "/sdcard/FIRST", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 2 || plan[1].Remote != "/sdcard/FIRST/paths/red/auto.json" {
+		t.Fatalf("pushPlan = %+v", plan)
+	}
+	if err := copyFiles(adb, plan, true); err != nil {
+		t.Fatal(err)
+	}
+	if adb.files["/sdcard/FIRST/paths/blue.json"] != "[]" {
+		t.Errorf("device files = %v", adb.files)
+	}
+
+	file := filepath.Join(dir, "blue.json")
+	if plan, _ := pushPlan(file, "/sdcard/FIRST/tflitemodels", true); plan[0].Remote != "/sdcard/FIRST/tflitemodels/blue.json" {
+		t.Errorf("push into dir = %s", plan[0].Remote)
+	}
+	if plan, _ := pushPlan(file, "/sdcard/FIRST/renamed.json", false); plan[0].Remote != "/sdcard/FIRST/renamed.json" {
+		t.Errorf("push to file = %s", plan[0].Remote)
+	}
+}
+
+func TestVerifyTransfers_Mismatch(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "a.txt")
+	os.WriteFile(local, []byte("local"), 0644)
+	adb := &fakeADB{files: map[string]string{"/sdcard/FIRST/a.txt": "remote"}}
+	bad, err := verifyTransfers(adb, []Transfer{{Local: local, Remote: "/sdcard/FIRST/a.txt"}})
+	if err != nil || len(bad) != 1 {
+		t.Errorf("verifyTransfers = %v, %v; want one mismatch", bad, err)
+	}
+}
+
+func TestTakeScreenshot(t *testing.T) {
+	png := append(append([]byte{}, pngSignature...), "data"...)
+	adb := &fakeADB{responses: map[string]string{"exec-out screencap -p": string(png)}}
+	got, err := takeScreenshot(adb)
+	if err != nil || !bytes.Equal(got, png) {
+		t.Errorf("takeScreenshot = %q, %v", got, err)
+	}
+	adb.responses["exec-out screencap -p"] = "error: no devices"
+	if _, err := takeScreenshot(adb); err == nil {
+		t.Error("expected error for non-PNG output")
+	}
+}
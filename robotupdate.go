@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Actions reported by planRCUpdate.
+const (
+	rcInstall   = "install"
+	rcUpgrade   = "upgrade"
+	rcDowngrade = "downgrade"
+	rcUpToDate  = "up to date"
+)
+
+// planRCUpdate decides what installing the target SDK release's Robot Controller app would do on a
+// device that has installed ("" when the app is missing).
+func planRCUpdate(installed, target string) string {
+	if installed == "" {
+		return rcInstall
+	}
+	switch compareVersions(installed, target) {
+	case -1:
+		return rcUpgrade
+	case 1:
+		return rcDowngrade
+	}
+	return rcUpToDate
+}
+
+// findRCAPK returns the Robot Controller APK attached to an SDK release.
+func findRCAPK(r *Release) (ReleaseAsset, bool) {
+	for _, a := range r.Assets {
+		name := strings.ToLower(a.Name)
+		if strings.HasSuffix(name, ".apk") && strings.Contains(name, "robotcontroller") {
+			return a, true
+		}
+	}
+	return ReleaseAsset{}, false
+}
+
+// cachedAPK returns the path of a release APK in cache_dir, downloading and verifying it first if needed.
+func cachedAPK(tag string, asset ReleaseAsset) (string, error) {
+	if err := validReleaseTag(tag); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(cacheDir(), "apk", tag)
+	p := filepath.Join(dir, filepath.Base(asset.Name))
+	if _, err := os.Stat(p); err == nil {
+		return p, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	fmt.Printf("Downloading %s...\n", asset.Name)
+	if err := downloadVerified(asset.DownloadURL, p+".part", strings.TrimPrefix(asset.Digest, "sha256:")); err != nil {
+		return "", err
+	}
+	return p, os.Rename(p+".part", p)
+}
+
+// installAPK installs apk on the device, replacing the existing app. Downgrades must be allowed explicitly.
+// adb install prints "Success" or "Failure [REASON]" and doesn't always exit non-zero on failure.
+func installAPK(adb ADB, apk string, downgrade bool) error {
+	args := []string{"install", "-r"}
+	if downgrade {
+		args = append(args, "-d")
+	}
+	out, err := adb.Run(append(args, apk)...)
+	text := strings.TrimSpace(string(out))
+	if err == nil && strings.Contains(text, "Success") {
+		return nil
+	}
+	if err != nil {
+		text = err.Error()
+	}
+	if strings.Contains(text, "INSTALL_FAILED_UPDATE_INCOMPATIBLE") {
+		return fmt.Errorf("the installed Robot Controller app is signed with a different key; uninstall it with 'adb uninstall %s' and run again", robotControllerPackage)
+	}
+	return fmt.Errorf("install failed: %s", valueOr(text, "no output from adb"))
+}
+
+var robotUpdateCmd = &cobra.Command{
+	Use:   "update [project_name]",
+	Short: "Install the Robot Controller app matching the project's SDK version",
+	Long: `Compares the Robot Controller app on the connected robot with the SDK version of the
+project (or the project in the current directory) and installs the Robot Controller APK
+from that FtcRobotController release with adb.
+
+Only the app is updated. Control Hub and Expansion Hub firmware can't be flashed over adb;
+update it with the REV Hardware Client.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		robotName, _ := cmd.Flags().GetString("robot")
+		target, _ := cmd.Flags().GetString("version")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		force, _ := cmd.Flags().GetBool("force")
+
+		if target == "" {
+			var projectPath string
+			if len(args) == 1 {
+				p, err := existingProject(args[0])
+				if err != nil {
+					fmt.Println(err)
+					return
+				}
+				projectPath = p
+			} else if p, ok := currentProject(); ok {
+				projectPath = p
+			} else {
+				fmt.Println("Not inside a project; give a project name or --version.")
+				return
+			}
+			version, source, err := detectSDKVersion(projectPath)
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			target = version
+			fmt.Printf("Project SDK:      %s (from %s)\n", target, source)
+		}
+		target = releaseTag(target)
+		if err := validReleaseTag(target); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		r, serial, adb, err := robotDevice(robotName)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		status, err := robotStatus(adb)
+		if err != nil {
+			fmt.Printf("%s (%s) is not responding: %v\n", r.Name, serial, err)
+			return
+		}
+		if status.RCVersion == "" && status.DSVersion != "" {
+			fmt.Printf("%s is running the Driver Station app; the Robot Controller app belongs on the Control Hub.\n", status.Model)
+			return
+		}
+
+		action := planRCUpdate(status.RCVersion, target)
+		fmt.Printf("Robot:            %s (%s, %s)\n", r.Name, serial, status.Model)
+		fmt.Printf("Robot Controller: %s -> %s (%s)\n", valueOr(status.RCVersion, "not installed"), target, action)
+		if action == rcUpToDate && !force {
+			fmt.Println("Nothing to do.")
+			return
+		}
+		if action == rcDowngrade && !force {
+			fmt.Println("The robot has a newer Robot Controller app than the project; use --force to downgrade it.")
+			return
+		}
+
+		release, err := fetchRelease(target)
+		if err != nil {
+			fmt.Println("Error fetching release:", err)
+			return
+		}
+		asset, ok := findRCAPK(release)
+		if !ok {
+			fmt.Printf("Release %s has no Robot Controller APK attached.\n", target)
+			return
+		}
+		fmt.Printf("APK:              %s (release %s)\n", asset.Name, target)
+		if dryRun {
+			fmt.Println("Dry run: nothing installed.")
+			return
+		}
+
+		apk, err := cachedAPK(target, asset)
+		if err != nil {
+			fmt.Println("Error downloading APK:", err)
+			return
+		}
+		fmt.Println("Installing...")
+		if err := installAPK(adb, apk, action == rcDowngrade); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		if v, err := packageVersion(adb, robotControllerPackage); err == nil && v != "" {
+			fmt.Println("Robot Controller is now", v)
+		}
+	},
+}
+
+func init() {
+	robotUpdateCmd.Flags().String("robot", "", "Saved robot to update (default: the active robot)")
+	robotUpdateCmd.Flags().String("version", "", "SDK release to install instead of the project's, e.g. v10.1")
+	robotUpdateCmd.Flags().BoolP("dry-run", "n", false, "Show what would change without installing")
+	robotUpdateCmd.Flags().Bool("force", false, "Reinstall the same version or allow a downgrade")
+	robotCmd.AddCommand(robotUpdateCmd)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestPlanRCUpdate(t *testing.T) {
+	tests := []struct {
+		installed, target, want string
+	}{
+		{"", "v10.1", rcInstall},
+		{"9.2", "v10.1", rcUpgrade},
+		{"10.1", "v10.1", rcUpToDate},
+		{"10.1.1", "v10.1", rcDowngrade},
+		{"10.1", "v10.1.1", rcUpgrade},
+	}
+	for _, tt := range tests {
+		if got := planRCUpdate(tt.installed, tt.target); got != tt.want {
+			t.Errorf("planRCUpdate(%q, %q) = %q, want %q", tt.installed, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestFindRCAPK(t *testing.T) {
+	r := &Release{TagName: "v10.1", Assets: []ReleaseAsset{
+		{Name: "FtcDriverStation-release.apk"},
+		{Name: "FtcRobotController-release.apk"},
+	}}
+	if a, ok := findRCAPK(r); !ok || a.Name != "FtcRobotController-release.apk" {
+		t.Errorf("findRCAPK = %+v, %v", a, ok)
+	}
+	if _, ok := findRCAPK(&Release{TagName: "v4.0"}); ok {
+		t.Error("expected no APK for a release without assets")
+	}
+}
+
+func TestInstallAPK(t *testing.T) {
+	tests := []struct {
+		name      string
+		downgrade bool
+		output    string
+		wantErr   string
+	}{
+		{"success", false, "Performing Streamed Install\nSuccess\n", ""},
+		{"downgrade", true, "Success\n", ""},
+		{"signature", false, "Failure [INSTALL_FAILED_UPDATE_INCOMPATIBLE: Package signatures do not match]", "adb uninstall"},
+		{"other failure", false, "Failure [INSTALL_FAILED_INSUFFICIENT_STORAGE]", "INSUFFICIENT_STORAGE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := "install -r rc.apk"
+			if tt.downgrade {
+				key = "install -r -d rc.apk"
+			}
+			adb := &fakeADB{responses: map[string]string{key: tt.output}}
+			err := installAPK(adb, "rc.apk", tt.downgrade)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("installAPK: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("installAPK error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetchReleaseAndCachedAPK(t *testing.T) {
+	apk := []byte("PK fake apk")
+	sum := sha256.Sum256(apk)
+	files := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(apk)
+	}))
+	defer files.Close()
+
+	fakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/FIRST-Tech-Challenge/FtcRobotController/releases/tags/v10.1" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"tag_name":"v10.1","assets":[{"name":"FtcRobotController-release.apk",` +
+			`"browser_download_url":"` + files.URL + `/rc.apk","digest":"sha256:` + hex.EncodeToString(sum[:]) + `"}]}`))
+	})
+	viper.Set("cache_dir", t.TempDir())
+	defer viper.Set("cache_dir", "")
+
+	r, err := fetchRelease("v10.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	asset, ok := findRCAPK(r)
+	if !ok {
+		t.Fatalf("no APK in %+v", r)
+	}
+	p, err := cachedAPK(r.TagName, asset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(p); string(b) != string(apk) {
+		t.Errorf("cached APK = %q", b)
+	}
+
+	asset.Digest = "sha256:" + strings.Repeat("0", 64)
+	asset.Name = "other.apk"
+	if _, err := cachedAPK(r.TagName, asset); err == nil {
+		t.Error("expected checksum mismatch")
+	}
+	if _, err := cachedAPK("../../x", asset); err == nil || !strings.Contains(err.Error(), "invalid release tag") {
+		t.Errorf("cachedAPK with a path in the tag: %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// samplesDir is where the SDK ships its sample OpModes, relative to the project root.
+const samplesDir = "FtcRobotController/src/main/java/org/firstinspires/ftc/robotcontroller/external/samples"
+
+const samplesPackage = "org.firstinspires.ftc.robotcontroller.external.samples"
+
+// Sample is a sample OpMode (or helper class) shipped with the SDK.
+type Sample struct {
+	Class       string
+	Name        string // OpMode name shown on the Driver Station, "" for helper classes
+	Kind        string // TeleOp or Autonomous
+	Description string
+	File        string
+}
+
+// summary is the first sentence of the description.
+func (s Sample) summary() string {
+	d := strings.Join(strings.Fields(s.Description), " ")
+	if i := strings.Index(d, ". "); i >= 0 {
+		d = d[:i+1]
+	}
+	if len(d) > 100 {
+		d = d[:97] + "..."
+	}
+	return d
+}
+
+var classDeclaration = regexp.MustCompile(`(?m)^(?:public\s+)?(?:abstract\s+)?(?:final\s+)?class\s+\w+`)
+
+var commentBlock = regexp.MustCompile(`(?s)/\*+(.*?)\*/`)
+
+// sampleDescription returns the comment above the class declaration and its annotations, which is
+// where the SDK samples explain what they demonstrate. The license header at the top of the file is skipped.
+func sampleDescription(src string) string {
+	end := len(src)
+	if loc := opModeAnnotation.FindStringIndex(src); loc != nil {
+		end = loc[0]
+	} else if loc := classDeclaration.FindStringIndex(src); loc != nil {
+		end = loc[0]
+	}
+	start := strings.Index(src, "\npackage ")
+	if start < 0 || start > end {
+		start = 0
+	}
+	blocks := commentBlock.FindAllStringSubmatch(src[start:end], -1)
+	if len(blocks) == 0 {
+		return ""
+	}
+	var lines []string
+	for _, l := range strings.Split(blocks[len(blocks)-1][1], "\n") {
+		l = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(l), "*"))
+		lines = append(lines, l)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// loadSamples reads the sample OpModes of a project's SDK.
+func loadSamples(projectPath string) ([]Sample, error) {
+	dir := filepath.Join(projectPath, filepath.FromSlash(samplesDir))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("no SDK samples in %s", projectPath)
+	}
+	var samples []Sample
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".java") {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		src := string(b)
+		s := Sample{
+			Class:       strings.TrimSuffix(e.Name(), ".java"),
+			Description: sampleDescription(src),
+			File:        filepath.Join(dir, e.Name()),
+		}
+		if modes := findOpModes(e.Name(), src); len(modes) > 0 {
+			s.Name, s.Kind = modes[0].Name, modes[0].Kind
+		}
+		samples = append(samples, s)
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Class < samples[j].Class })
+	return samples, nil
+}
+
+// findSample looks a sample up by class name or Driver Station name, ignoring case.
+func findSample(samples []Sample, name string) (Sample, bool) {
+	for _, s := range samples {
+		if strings.EqualFold(s.Class, name) || (s.Name != "" && strings.EqualFold(s.Name, name)) {
+			return s, true
+		}
+	}
+	return Sample{}, false
+}
+
+var disabledLine = regexp.MustCompile(`(?m)^[ \t]*@Disabled[ \t]*\r?\n`)
+
+var disabledImport = regexp.MustCompile(`(?m)^import com\.qualcomm\.robotcore\.eventloop\.opmode\.Disabled;[ \t]*\r?\n`)
+
+// adaptSample rewrites a sample for TeamCode: the package becomes org.firstinspires.ftc.teamcode,
+// @Disabled is removed so it shows up on the Driver Station, and the class is renamed to className.
+func adaptSample(src, class, className string) string {
+	src = strings.Replace(src, "package "+samplesPackage+";", "package org.firstinspires.ftc.teamcode;", 1)
+	src = disabledLine.ReplaceAllString(src, "")
+	src = disabledImport.ReplaceAllString(src, "")
+	if className != class {
+		src = regexp.MustCompile(`\b`+regexp.QuoteMeta(class)+`\b`).ReplaceAllString(src, className)
+	}
+	return src
+}
+
+// sampleDependencies returns the other samples a sample's source refers to, which have to be copied too.
+func sampleDependencies(src string, self Sample, samples []Sample) []string {
+	var deps []string
+	for _, s := range samples {
+		if s.Class != self.Class && regexp.MustCompile(`\b`+regexp.QuoteMeta(s.Class)+`\b`).MatchString(src) {
+			deps = append(deps, s.Class)
+		}
+	}
+	return deps
+}
+
+// samples: browse the SDK's sample OpModes offline
+var samplesCmd = &cobra.Command{
+	Use:   "samples [project_name]",
+	Short: "List the sample OpModes shipped with a project's SDK",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filter, _ := cmd.Flags().GetString("filter")
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		samples, err := loadSamples(projectPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		filter = strings.ToLower(filter)
+		var shown int
+		for _, s := range samples {
+			text := strings.ToLower(s.Class + " " + s.Name + " " + s.Description)
+			if filter != "" && !strings.Contains(text, filter) {
+				continue
+			}
+			fmt.Printf("%-40s %-10s %s\n", s.Class, valueOr(s.Kind, "-"), s.summary())
+			shown++
+		}
+		fmt.Printf("\n%d sample(s). Show one with 'ftc-helper samples show %s <Name>'.\n", shown, args[0])
+	},
+}
+
+var samplesShowCmd = &cobra.Command{
+	Use:   "show [project_name] [Name]",
+	Short: "Show a sample's description and source",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		samples, err := loadSamples(projectPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		s, ok := findSample(samples, args[1])
+		if !ok {
+			fmt.Printf("No sample named '%s'.\n", args[1])
+			return
+		}
+		src, err := os.ReadFile(s.File)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Println(s.Class)
+		if s.Name != "" {
+			fmt.Printf("%s \"%s\"\n", s.Kind, s.Name)
+		}
+		if s.Description != "" {
+			fmt.Println()
+			fmt.Println(s.Description)
+		}
+		fmt.Println()
+		fmt.Println(strings.Repeat("-", 72))
+		fmt.Print(string(src))
+	},
+}
+
+var samplesCopyCmd = &cobra.Command{
+	Use:   "copy [project_name] [Name]",
+	Short: "Copy a sample into TeamCode, enabled and in the teamcode package",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		className, _ := cmd.Flags().GetString("as")
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		samples, err := loadSamples(projectPath)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		s, ok := findSample(samples, args[1])
+		if !ok {
+			fmt.Printf("No sample named '%s'.\n", args[1])
+			return
+		}
+		if className == "" {
+			className = s.Class
+		}
+		if !javaIdentifierRe.MatchString(className) {
+			fmt.Printf("Invalid class name '%s'.\n", className)
+			return
+		}
+
+		out := filepath.Join(teamCodeDir(args[0]), className+".java")
+		if _, err := os.Stat(out); err == nil {
+			fmt.Println("File already exists:", out)
+			return
+		}
+		b, err := os.ReadFile(s.File)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		if err := os.WriteFile(out, []byte(adaptSample(string(b), s.Class, className)), 0644); err != nil {
+			fmt.Println("Error writing OpMode:", err)
+			return
+		}
+		fmt.Println("Created", out)
+		if deps := sampleDependencies(string(b), s, samples); len(deps) > 0 {
+			fmt.Printf("This sample also uses %s; copy those with 'ftc-helper samples copy' too.\n", strings.Join(deps, ", "))
+		}
+	},
+}
+
+func init() {
+	samplesCmd.Flags().StringP("filter", "f", "", "Only list samples mentioning this text, e.g. imu or apriltag")
+	samplesCopyCmd.Flags().String("as", "", "Class name for the copy (default: the sample's name)")
+	samplesCmd.AddCommand(samplesShowCmd, samplesCopyCmd)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSample = `/* Copyright (c) 2017 FIRST. All rights reserved.
+ *
+ * Redistribution and use in source and binary forms...
+ */
+
+package org.firstinspires.ftc.robotcontroller.external.samples;
+
+import com.qualcomm.robotcore.eventloop.opmode.Disabled;
+import com.qualcomm.robotcore.eventloop.opmode.LinearOpMode;
+import com.qualcomm.robotcore.eventloop.opmode.TeleOp;
+
+/*
+ * This OpMode scans a single servo back and forward until Stop is pressed.
+ * The code is structured as a LinearOpMode.
+ */
+@TeleOp(name = "Concept: Scan Servo", group = "Concept")
+@Disabled
+public class ConceptScanServo extends LinearOpMode {
+    RobotHardware robot = new RobotHardware(this);
+
+    public ConceptScanServo() {}
+
+    @Override
+    public void runOpMode() {
+        waitForStart();
+    }
+}
+`
+
+func writeTestSamples(t *testing.T, projectPath string) {
+	t.Helper()
+	dir := filepath.Join(projectPath, filepath.FromSlash(samplesDir))
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "ConceptScanServo.java"), []byte(testSample), 0644)
+	os.WriteFile(filepath.Join(dir, "RobotHardware.java"), []byte("package "+samplesPackage+";\n\n/** Holds the robot's hardware. */\npublic class RobotHardware {}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "readme.md"), []byte("# Samples"), 0644)
+}
+
+func TestLoadSamples(t *testing.T) {
+	root := makeTestProject(t, t.TempDir(), "robot")
+	writeTestSamples(t, root)
+
+	samples, err := loadSamples(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("loadSamples = %+v, want 2 samples", samples)
+	}
+	s := samples[0]
+	if s.Class != "ConceptScanServo" || s.Name != "Concept: Scan Servo" || s.Kind != "TeleOp" {
+		t.Errorf("sample = %+v", s)
+	}
+	if want := "This OpMode scans a single servo back and forward until Stop is pressed."; s.summary() != want {
+		t.Errorf("summary = %q, want %q", s.summary(), want)
+	}
+	if samples[1].Description != "Holds the robot's hardware." || samples[1].Kind != "" {
+		t.Errorf("helper sample = %+v", samples[1])
+	}
+
+	if _, ok := findSample(samples, "concept: scan servo"); !ok {
+		t.Error("findSample by OpMode name failed")
+	}
+	if _, err := loadSamples(t.TempDir()); err == nil {
+		t.Error("expected error for a project without samples")
+	}
+}
+
+func TestAdaptSample(t *testing.T) {
+	got := adaptSample(testSample, "ConceptScanServo", "ScanServo")
+	for _, want := range []string{
+		"package org.firstinspires.ftc.teamcode;",
+		"public class ScanServo extends LinearOpMode",
+		"public ScanServo() {}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("adapted sample missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"@Disabled", "opmode.Disabled;", "external.samples", "ConceptScanServo"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("adapted sample still contains %q", unwanted)
+		}
+	}
+}
+
+func TestSampleDependencies(t *testing.T) {
+	samples := []Sample{{Class: "ConceptScanServo"}, {Class: "RobotHardware"}, {Class: "Robot"}}
+	deps := sampleDependencies(testSample, samples[0], samples)
+	if len(deps) != 1 || deps[0] != "RobotHardware" {
+		t.Errorf("sampleDependencies = %v, want [RobotHardware]", deps)
+	}
+}
@@ -0,0 +1,646 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// sdkArchiveURL is the source archive of an FtcRobotController release. A variable so tests can point it elsewhere.
+var sdkArchiveURL = "https://github.com/FIRST-Tech-Challenge/FtcRobotController/archive/refs/tags/%s.zip"
+
+// cacheDir returns the directory for downloaded files (cache_dir).
+func cacheDir() string {
+	if d := viper.GetString("cache_dir"); d != "" {
+		return d
+	}
+	return filepath.Join(os.TempDir(), "ftc-helper")
+}
+
+// releaseTagPattern matches FtcRobotController release tags such as v10.1 or 9.0.1.
+var releaseTagPattern = regexp.MustCompile(`^v?\d+(\.\d+)*$`)
+
+// validReleaseTag rejects tags that aren't plain release versions, since tags become file names in
+// the cache.
+func validReleaseTag(tag string) error {
+	if !releaseTagPattern.MatchString(tag) {
+		return fmt.Errorf("invalid release tag %q (expected a version like v10.1)", tag)
+	}
+	return nil
+}
+
+// sdkArchive returns the path of the cached release archive for tag, downloading it first if needed.
+func sdkArchive(tag string) (string, error) {
+	if err := validReleaseTag(tag); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(cacheDir(), "sdk")
+	p := filepath.Join(dir, tag+".zip")
+	if _, err := os.Stat(p); err == nil {
+		return p, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf(sdkArchiveURL, tag)
+	fmt.Printf("Downloading %s...\n", url)
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("downloading %s: %w", tag, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading %s: received status code %d", tag, resp.StatusCode)
+	}
+
+	// Download next to the final name so an interrupted download is never mistaken for a cached one.
+	tmp, err := os.CreateTemp(dir, tag+"-*.part")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("downloading %s: %w", tag, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	return p, os.Rename(tmp.Name(), p)
+}
+
+// readSDKArchive reads every file of a release archive into memory, keyed by slash-separated path with
+// the top-level "FtcRobotController-<version>" directory removed.
+func readSDKArchive(p string) (map[string][]byte, error) {
+	r, err := zip.OpenReader(p)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	files := map[string][]byte{}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := f.Name
+		if i := strings.Index(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[name] = b
+	}
+	return files, nil
+}
+
+// TreeDiff lists the paths that differ between two file trees.
+type TreeDiff struct {
+	Added, Removed, Changed []string
+}
+
+func diffTrees(from, to map[string][]byte) TreeDiff {
+	var d TreeDiff
+	for name, a := range from {
+		b, ok := to[name]
+		switch {
+		case !ok:
+			d.Removed = append(d.Removed, name)
+		case !bytes.Equal(a, b):
+			d.Changed = append(d.Changed, name)
+		}
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			d.Added = append(d.Added, name)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+	return d
+}
+
+// gradleCoordinate matches quoted "group:artifact:version" strings in Gradle files.
+var gradleCoordinate = regexp.MustCompile(`['"]([\w.\-]+:[\w.\-]+):([\w.\-+]+)['"]`)
+
+// gradleSetting matches simple numeric/string settings such as compileSdkVersion 30 or minSdk = 24.
+var gradleSetting = regexp.MustCompile(`(?m)^\s*((?:compile|min|target)Sdk(?:Version)?|buildToolsVersion|ndkVersion|versionName|versionCode)\s*=?\s*['"]?([\w.\-]+)['"]?\s*$`)
+
+// gradleVersions extracts dependency and SDK level versions from the Gradle files of a tree,
+// keyed by "group:artifact" or "file: setting".
+func gradleVersions(files map[string][]byte) map[string]string {
+	versions := map[string]string{}
+	for name, content := range files {
+		if !strings.HasSuffix(name, ".gradle") && !strings.HasSuffix(name, ".gradle.kts") {
+			continue
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			line = stripGradleComment(line)
+			for _, m := range gradleCoordinate.FindAllStringSubmatch(line, -1) {
+				versions[m[1]] = m[2]
+			}
+			if m := gradleSetting.FindStringSubmatch(line); m != nil {
+				versions[name+": "+m[1]] = m[2]
+			}
+		}
+	}
+	return versions
+}
+
+// VersionChange is a dependency or setting whose version differs between releases.
+// From or To is empty when it was added or removed.
+type VersionChange struct {
+	Name, From, To string
+}
+
+func diffVersions(from, to map[string]string) []VersionChange {
+	var changes []VersionChange
+	for name, a := range from {
+		if b := to[name]; a != b {
+			changes = append(changes, VersionChange{name, a, b})
+		}
+	}
+	for name, b := range to {
+		if _, ok := from[name]; !ok {
+			changes = append(changes, VersionChange{name, "", b})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// sdkAPIDirs are the parts of the SDK whose public API TeamCode builds against or copies from.
+var sdkAPIDirs = []string{"FtcRobotController/"}
+
+func isSDKAPIFile(name string) bool {
+	if !strings.HasSuffix(name, ".java") {
+		return false
+	}
+	for _, dir := range sdkAPIDirs {
+		if strings.HasPrefix(name, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// publicDeclaration matches public class, interface, enum, field and method declarations on one line.
+var publicDeclaration = regexp.MustCompile(`^\s*public\s+[^=;{]*?(?:\([^)]*\)|\b(?:class|interface|enum)\s+\w+)`)
+
+// publicAPI returns the normalised public declarations in Java source.
+func publicAPI(src []byte) []string {
+	var decls []string
+	for _, line := range strings.Split(string(src), "\n") {
+		if m := publicDeclaration.FindString(line); m != "" {
+			decls = append(decls, strings.Join(strings.Fields(m), " "))
+		}
+	}
+	return decls
+}
+
+// APIChange lists the public declarations added and removed in one file.
+type APIChange struct {
+	File           string
+	Added, Removed []string
+	FileAdded      bool
+	FileRemoved    bool
+}
+
+// diffAPI compares the public declarations of SDK and sample sources between two trees.
+func diffAPI(from, to map[string][]byte, tree TreeDiff) []APIChange {
+	var changes []APIChange
+	for _, name := range tree.Added {
+		if isSDKAPIFile(name) {
+			changes = append(changes, APIChange{File: name, Added: publicAPI(to[name]), FileAdded: true})
+		}
+	}
+	for _, name := range tree.Removed {
+		if isSDKAPIFile(name) {
+			changes = append(changes, APIChange{File: name, Removed: publicAPI(from[name]), FileRemoved: true})
+		}
+	}
+	for _, name := range tree.Changed {
+		if !isSDKAPIFile(name) {
+			continue
+		}
+		added, removed := diffSets(publicAPI(from[name]), publicAPI(to[name]))
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, APIChange{File: name, Added: added, Removed: removed})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].File < changes[j].File })
+	return changes
+}
+
+// diffSets returns the entries only in b (added) and only in a (removed), keeping their order.
+func diffSets(a, b []string) (added, removed []string) {
+	inA := map[string]bool{}
+	for _, s := range a {
+		inA[s] = true
+	}
+	inB := map[string]bool{}
+	for _, s := range b {
+		inB[s] = true
+		if !inA[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range a {
+		if !inB[s] {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+// maxDiffCells bounds the memory used by unifiedDiff; larger changes are shown as a single replacement.
+const maxDiffCells = 4 << 20
+
+// unifiedDiff returns a unified diff of two texts with the given number of context lines, or "" if equal.
+func unifiedDiff(fromName, toName, a, b string, context int) string {
+	if a == b {
+		return ""
+	}
+	x, y := splitLines(a), splitLines(b)
+
+	// Trim the common prefix and suffix; the LCS only needs to run on what is left.
+	pre := 0
+	for pre < len(x) && pre < len(y) && x[pre] == y[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(x)-pre && suf < len(y)-pre && x[len(x)-1-suf] == y[len(y)-1-suf] {
+		suf++
+	}
+	mx, my := x[pre:len(x)-suf], y[pre:len(y)-suf]
+
+	// ops is the edit script over the whole file: ' ' keep, '-' delete, '+' insert.
+	type op struct {
+		kind byte
+		line string
+	}
+	var ops []op
+	for _, l := range x[:pre] {
+		ops = append(ops, op{' ', l})
+	}
+	if len(mx)*len(my) > maxDiffCells {
+		for _, l := range mx {
+			ops = append(ops, op{'-', l})
+		}
+		for _, l := range my {
+			ops = append(ops, op{'+', l})
+		}
+	} else {
+		// lcs[i][j] is the LCS length of mx[i:] and my[j:].
+		lcs := make([][]int32, len(mx)+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, len(my)+1)
+		}
+		for i := len(mx) - 1; i >= 0; i-- {
+			for j := len(my) - 1; j >= 0; j-- {
+				if mx[i] == my[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(mx) || j < len(my) {
+			switch {
+			case i < len(mx) && j < len(my) && mx[i] == my[j]:
+				ops = append(ops, op{' ', mx[i]})
+				i++
+				j++
+			case i < len(mx) && (j == len(my) || lcs[i+1][j] >= lcs[i][j+1]):
+				ops = append(ops, op{'-', mx[i]})
+				i++
+			default:
+				ops = append(ops, op{'+', my[j]})
+				j++
+			}
+		}
+	}
+	for _, l := range x[len(x)-suf:] {
+		ops = append(ops, op{' ', l})
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(ops); {
+		// Find the next change and extend the hunk while changes are within 2*context lines of each other.
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		last := first
+		for k := first; k < len(ops); k++ {
+			if ops[k].kind != ' ' {
+				last = k
+			} else if k-last > 2*context {
+				break
+			}
+		}
+		lo := first - context
+		if lo < start {
+			lo = start
+		}
+		hi := last + context + 1
+		if hi > len(ops) {
+			hi = len(ops)
+		}
+
+		// Line numbers of the hunk start in each file.
+		aLine, bLine := 1, 1
+		for _, o := range ops[:lo] {
+			if o.kind != '+' {
+				aLine++
+			}
+			if o.kind != '-' {
+				bLine++
+			}
+		}
+		aCount, bCount := 0, 0
+		for _, o := range ops[lo:hi] {
+			if o.kind != '+' {
+				aCount++
+			}
+			if o.kind != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+		for _, o := range ops[lo:hi] {
+			fmt.Fprintf(&out, "%c%s\n", o.kind, o.line)
+		}
+		start = hi
+	}
+	return out.String()
+}
+
+func hunkRange(line, count int) string {
+	if count == 0 {
+		line--
+	}
+	if count == 1 {
+		return fmt.Sprint(line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// isBinary reports whether content looks like a binary file.
+func isBinary(b []byte) bool {
+	if len(b) > 8000 {
+		b = b[:8000]
+	}
+	return bytes.IndexByte(b, 0) >= 0
+}
+
+// sdk: commands for FtcRobotController releases
+var sdkCmd = &cobra.Command{
+	Use:   "sdk",
+	Short: "Inspect FtcRobotController SDK releases",
+}
+
+var sdkDiffCmd = &cobra.Command{
+	Use:   "diff [fromTag] [toTag]",
+	Short: "Show what changed between two SDK releases",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		unified, _ := cmd.Flags().GetBool("unified")
+		context, _ := cmd.Flags().GetInt("context")
+		pathPrefix, _ := cmd.Flags().GetString("path")
+
+		trees := make([]map[string][]byte, 2)
+		for i, tag := range args {
+			p, err := sdkArchive(tag)
+			if err != nil {
+				fmt.Println("Error getting release:", err)
+				return
+			}
+			if trees[i], err = readSDKArchive(p); err != nil {
+				fmt.Printf("Error reading %s: %v\n", tag, err)
+				return
+			}
+		}
+		from, to := trees[0], trees[1]
+
+		tree := diffTrees(from, to)
+		if pathPrefix != "" {
+			keep := func(names []string) []string {
+				var out []string
+				for _, n := range names {
+					if strings.HasPrefix(n, pathPrefix) {
+						out = append(out, n)
+					}
+				}
+				return out
+			}
+			tree = TreeDiff{keep(tree.Added), keep(tree.Removed), keep(tree.Changed)}
+		}
+
+		fmt.Printf("SDK %s -> %s: %d added, %d removed, %d changed\n", args[0], args[1], len(tree.Added), len(tree.Removed), len(tree.Changed))
+		for _, section := range []struct {
+			mark  string
+			names []string
+		}{{"A", tree.Added}, {"D", tree.Removed}, {"M", tree.Changed}} {
+			for _, n := range section.names {
+				fmt.Printf("  %s %s\n", section.mark, n)
+			}
+		}
+
+		if changes := diffVersions(gradleVersions(from), gradleVersions(to)); len(changes) > 0 {
+			fmt.Println("\nGradle versions:")
+			for _, c := range changes {
+				fmt.Printf("  %-60s %s -> %s\n", c.Name, valueOr(c.From, "(none)"), valueOr(c.To, "(removed)"))
+			}
+		}
+
+		if api := diffAPI(from, to, tree); len(api) > 0 {
+			fmt.Println("\nSDK and sample API changes:")
+			for _, c := range api {
+				switch {
+				case c.FileAdded:
+					fmt.Printf("  %s (new)\n", c.File)
+				case c.FileRemoved:
+					fmt.Printf("  %s (removed)\n", c.File)
+				default:
+					fmt.Printf("  %s\n", c.File)
+				}
+				for _, d := range c.Removed {
+					fmt.Printf("    - %s\n", d)
+				}
+				for _, d := range c.Added {
+					fmt.Printf("    + %s\n", d)
+				}
+			}
+		}
+
+		if !unified {
+			return
+		}
+		fmt.Println()
+		names := append(append(append([]string{}, tree.Changed...), tree.Added...), tree.Removed...)
+		sort.Strings(names)
+		for _, n := range names {
+			a, b := from[n], to[n]
+			if isBinary(a) || isBinary(b) {
+				fmt.Printf("Binary file %s differs\n", n)
+				continue
+			}
+			fromName, toName := args[0]+"/"+n, args[1]+"/"+n
+			if a == nil {
+				fromName = "/dev/null"
+			}
+			if b == nil {
+				toName = "/dev/null"
+			}
+			fmt.Print(unifiedDiff(fromName, toName, string(a), string(b), context))
+		}
+	},
+}
+
+func init() {
+	sdkDiffCmd.Flags().BoolP("unified", "u", false, "Also print a unified diff of every changed file")
+	sdkDiffCmd.Flags().IntP("context", "U", 3, "Lines of context in the unified diff")
+	sdkDiffCmd.Flags().String("path", "", "Only compare files under this path, e.g. FtcRobotController/")
+	sdkCmd.AddCommand(sdkDiffCmd)
+}
+
+// ftcArtifactVersion matches the SDK artifacts in build.dependencies.gradle, e.g. 'org.firstinspires.ftc:RobotCore:10.1.0'.
+var ftcArtifactVersion = regexp.MustCompile(`org\.firstinspires\.ftc:\w+:(\d+(?:\.\d+)*)`)
+
+// manifestVersionName matches android:versionName in the FtcRobotController AndroidManifest.xml.
+var manifestVersionName = regexp.MustCompile(`android:versionName\s*=\s*"([^"]+)"`)
+
+// detectSDKVersion works out which FtcRobotController release a project is based on, for projects
+// created outside ftc-helper. It tries the project manifest, then the SDK artifact versions in
+// build.dependencies.gradle, then the versionName of the FtcRobotController module. The second
+// result names where the version came from.
+func detectSDKVersion(projectPath string) (string, string, error) {
+	if m, err := readManifest(projectPath); err == nil && m.SDKVersion != "" {
+		return m.SDKVersion, manifestFile, nil
+	}
+
+	if b, err := os.ReadFile(filepath.Join(projectPath, "build.dependencies.gradle")); err == nil {
+		var best string
+		for _, line := range strings.Split(string(b), "\n") {
+			line = stripGradleComment(line)
+			if m := ftcArtifactVersion.FindStringSubmatch(line); m != nil && compareVersions(m[1], best) > 0 {
+				best = m[1]
+			}
+		}
+		if best != "" {
+			return releaseTag(best), "build.dependencies.gradle", nil
+		}
+	}
+
+	for _, f := range []string{"FtcRobotController/src/main/AndroidManifest.xml", "FtcRobotController/build.gradle"} {
+		if b, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(f))); err == nil {
+			if m := manifestVersionName.FindSubmatch(b); m != nil {
+				return releaseTag(string(m[1])), f, nil
+			}
+			if m := gradleSetting.FindAllSubmatch(b, -1); m != nil {
+				for _, s := range m {
+					if string(s[1]) == "versionName" {
+						return releaseTag(string(s[2])), f, nil
+					}
+				}
+			}
+		}
+	}
+	return "", "", fmt.Errorf("could not detect the SDK version of %s", projectPath)
+}
+
+// releaseTag turns an artifact or app version such as "10.1.0" into the release tag "v10.1".
+// Releases are tagged without a trailing ".0" patch level.
+func releaseTag(version string) string {
+	version = strings.TrimPrefix(version, "v")
+	if parts := strings.Split(version, "."); len(parts) == 3 && parts[2] == "0" {
+		version = parts[0] + "." + parts[1]
+	}
+	return "v" + version
+}
+
+// compareVersions compares dotted version numbers such as "v10.1" and "10.1.1", returning -1, 0 or 1.
+// Missing components count as zero and an empty version is older than any other.
+func compareVersions(a, b string) int {
+	if a == "" || b == "" {
+		switch {
+		case a == b:
+			return 0
+		case a == "":
+			return -1
+		default:
+			return 1
+		}
+	}
+	pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			fmt.Sscanf(pa[i], "%d", &x)
+		}
+		if i < len(pb) {
+			fmt.Sscanf(pb[i], "%d", &y)
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// latestRelease returns the newest FtcRobotController release tag.
+func latestRelease() (string, error) {
+	releases, err := fetchReleases()
+	if err != nil {
+		return "", err
+	}
+	var latest string
+	for _, r := range releases {
+		if compareVersions(r.TagName, latest) > 0 {
+			latest = r.TagName
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no releases found")
+	}
+	return latest, nil
+}
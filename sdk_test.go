@@ -0,0 +1,236 @@
+package main
+
+import (
+	"archive/zip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestSDKArchive_Caches(t *testing.T) {
+	var buf strings.Builder
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("FtcRobotController-10.1/build.gradle")
+	w.Write([]byte("// sdk"))
+	zw.Close()
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/v10.1.zip" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(buf.String()))
+	}))
+	defer srv.Close()
+
+	oldURL := sdkArchiveURL
+	sdkArchiveURL = srv.URL + "/%s.zip"
+	defer func() { sdkArchiveURL = oldURL }()
+	viper.Set("cache_dir", t.TempDir())
+	defer viper.Set("cache_dir", "")
+
+	for i := 0; i < 2; i++ {
+		p, err := sdkArchive("v10.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err := readSDKArchive(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(files["build.gradle"]) != "// sdk" {
+			t.Errorf("files = %v", files)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("downloaded %d times, want 1", requests)
+	}
+
+	if _, err := sdkArchive("v0.0"); err == nil {
+		t.Error("expected error for missing release")
+	}
+	for _, tag := range []string{"../../x", "v10.1/../../x", "", "main"} {
+		if _, err := sdkArchive(tag); err == nil {
+			t.Errorf("sdkArchive(%q): expected invalid tag error", tag)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("invalid tags were requested: %d requests", requests)
+	}
+	entries, _ := os.ReadDir(filepath.Join(cacheDir(), "sdk"))
+	if len(entries) != 1 {
+		t.Errorf("cache should only hold the good archive, has %d entries", len(entries))
+	}
+}
+
+func TestDiffTrees(t *testing.T) {
+	from := map[string][]byte{"a": []byte("1"), "b": []byte("2"), "c": []byte("3")}
+	to := map[string][]byte{"a": []byte("1"), "b": []byte("changed"), "d": []byte("4")}
+	d := diffTrees(from, to)
+	if strings.Join(d.Added, ",") != "d" || strings.Join(d.Removed, ",") != "c" || strings.Join(d.Changed, ",") != "b" {
+		t.Errorf("diffTrees = %+v", d)
+	}
+}
+
+func TestGradleVersions(t *testing.T) {
+	from := map[string][]byte{
+		"build.dependencies.gradle": []byte(`dependencies {
+    implementation 'org.firstinspires.ftc:RobotCore:10.0.0'
+    implementation 'org.firstinspires.ftc:Hardware:10.0.0'
+    // implementation 'com.example:commented:1.0'
+}`),
+		"build.common.gradle": []byte("    compileSdkVersion 29\n    minSdkVersion 24\n"),
+	}
+	to := map[string][]byte{
+		"build.dependencies.gradle": []byte(`dependencies {
+    implementation 'org.firstinspires.ftc:RobotCore:10.1.0'
+    implementation 'org.firstinspires.ftc:Vision:10.1.0'
+}`),
+		"build.common.gradle": []byte("    compileSdkVersion 30\n    minSdkVersion 24\n"),
+	}
+
+	var got []string
+	for _, c := range diffVersions(gradleVersions(from), gradleVersions(to)) {
+		got = append(got, c.Name+" "+c.From+"->"+c.To)
+	}
+	want := []string{
+		"build.common.gradle: compileSdkVersion 29->30",
+		"org.firstinspires.ftc:Hardware 10.0.0->",
+		"org.firstinspires.ftc:RobotCore 10.0.0->10.1.0",
+		"org.firstinspires.ftc:Vision ->10.1.0",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diffVersions =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDiffAPI(t *testing.T) {
+	from := map[string][]byte{
+		"FtcRobotController/src/Sample.java": []byte("public class Sample {\n    public void run(int x) {\n    }\n    public void old() {}\n}\n"),
+		"TeamCode/src/Other.java":            []byte("public class Other {}"),
+	}
+	to := map[string][]byte{
+		"FtcRobotController/src/Sample.java": []byte("public class Sample {\n    public void run(int x) {\n        // more\n    }\n    public void   runFast(double  speed) {}\n}\n"),
+		"TeamCode/src/Other.java":            []byte("public class Other { public void x() {} }"),
+	}
+	api := diffAPI(from, to, diffTrees(from, to))
+	if len(api) != 1 {
+		t.Fatalf("diffAPI = %+v", api)
+	}
+	c := api[0]
+	if strings.Join(c.Added, ",") != "public void runFast(double speed)" || strings.Join(c.Removed, ",") != "public void old()" {
+		t.Errorf("diffAPI = %+v", c)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+	got := unifiedDiff("a/f", "b/f", a, b, 1)
+	want := `--- a/f
++++ b/f
+@@ -2,3 +2,3 @@
+ two
+-three
++THREE
+ four
+@@ -10 +10,2 @@
+ ten
++eleven
+`
+	if got != want {
+		t.Errorf("unifiedDiff =\n%s\nwant\n%s", got, want)
+	}
+	if unifiedDiff("a", "b", a, a, 3) != "" {
+		t.Error("equal texts should have no diff")
+	}
+	if got := unifiedDiff("/dev/null", "b/f", "", "new\n", 3); !strings.Contains(got, "@@ -0,0 +1 @@\n+new\n") {
+		t.Errorf("new file diff =\n%s", got)
+	}
+}
+
+func TestDetectSDKVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		want, from string
+	}{
+		{
+			name:  "manifest",
+			files: map[string]string{manifestFile: "sdk_version: v10.1\n", "build.dependencies.gradle": "implementation 'org.firstinspires.ftc:RobotCore:9.0.0'"},
+			want:  "v10.1", from: manifestFile,
+		},
+		{
+			name: "dependencies",
+			files: map[string]string{"build.dependencies.gradle": `dependencies {
+    implementation 'org.firstinspires.ftc:Inspection:10.1.1'
+    implementation 'org.firstinspires.ftc:RobotCore:10.1.1'
+    // implementation 'org.firstinspires.ftc:Old:11.0.0'
+    implementation 'androidx.appcompat:appcompat:1.2.0'
+}`},
+			want: "v10.1.1", from: "build.dependencies.gradle",
+		},
+		{
+			name:  "android manifest",
+			files: map[string]string{"FtcRobotController/src/main/AndroidManifest.xml": `<manifest android:versionCode="56" android:versionName="9.2">`},
+			want:  "v9.2", from: "FtcRobotController/src/main/AndroidManifest.xml",
+		},
+		{
+			name:  "unknown",
+			files: map[string]string{"build.gradle": "// nothing"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for p, content := range tt.files {
+				full := filepath.Join(dir, filepath.FromSlash(p))
+				os.MkdirAll(filepath.Dir(full), 0755)
+				os.WriteFile(full, []byte(content), 0644)
+			}
+			got, from, err := detectSDKVersion(dir)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want || from != tt.from {
+				t.Errorf("detectSDKVersion = %q, %q, %v; want %q, %q", got, from, err, tt.want, tt.from)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v10.1", "v10.1", 0},
+		{"v10.1", "10.1.0", 0},
+		{"v9.2", "v10.0", -1},
+		{"v10.1.1", "v10.1", 1},
+		{"", "v8.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestReleaseTag(t *testing.T) {
+	for in, want := range map[string]string{"10.1.0": "v10.1", "10.1.1": "v10.1.1", "9.2": "v9.2", "v8.0.0": "v8.0"} {
+		if got := releaseTag(in); got != want {
+			t.Errorf("releaseTag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
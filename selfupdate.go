@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// selfUpdateRepo is where ftc-helper releases are published.
+const selfUpdateRepo = "Harnish/ftc-helper"
+
+// checksumsAsset is the optional release asset listing "<sha256>  <file>" for every binary.
+const checksumsAsset = "checksums.txt"
+
+// ReleaseAsset is a file attached to a GitHub release.
+type ReleaseAsset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+	// Digest is "sha256:<hex>", computed by GitHub for assets uploaded since mid-2025.
+	Digest string `json:"digest"`
+}
+
+// HelperRelease is a release of ftc-helper itself.
+type HelperRelease struct {
+	TagName string         `json:"tag_name"`
+	Assets  []ReleaseAsset `json:"assets"`
+}
+
+// fetchHelperRelease returns the latest release, or the release tagged version when it is not empty.
+func fetchHelperRelease(version string) (*HelperRelease, error) {
+	endpoint := "/repos/" + selfUpdateRepo + "/releases/latest"
+	if version != "" {
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		endpoint = "/repos/" + selfUpdateRepo + "/releases/tags/" + version
+	}
+	var r HelperRelease
+	if err := githubDo("GET", endpoint, nil, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// helperAssetName is the binary name the release workflow uses for a platform, e.g. ftc-helper-windows-amd64.exe.
+func helperAssetName(goos, goarch string) string {
+	name := "ftc-helper-" + goos + "-" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// findAsset returns the asset with the given name.
+func (r *HelperRelease) findAsset(name string) (ReleaseAsset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return ReleaseAsset{}, false
+}
+
+var sha256Hex = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// parseChecksums reads sha256sum output ("<hex>  <name>" or "<hex> *<name>") into a map by file name.
+func parseChecksums(r io.Reader) map[string]string {
+	sums := map[string]string{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 2 || !sha256Hex.MatchString(fields[0]) {
+			continue
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums
+}
+
+// expectedChecksum returns the SHA-256 of an asset from the release's checksums.txt, falling back to
+// the digest GitHub reports for the asset.
+func expectedChecksum(r *HelperRelease, asset ReleaseAsset) (string, error) {
+	if sums, ok := r.findAsset(checksumsAsset); ok {
+		resp, err := http.Get(sums.DownloadURL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("downloading %s: status %d", checksumsAsset, resp.StatusCode)
+		}
+		if sum, ok := parseChecksums(resp.Body)[asset.Name]; ok {
+			return sum, nil
+		}
+		return "", fmt.Errorf("%s has no entry for %s", checksumsAsset, asset.Name)
+	}
+	if d := strings.TrimPrefix(asset.Digest, "sha256:"); sha256Hex.MatchString(d) {
+		return strings.ToLower(d), nil
+	}
+	return "", fmt.Errorf("release %s publishes no checksum for %s", r.TagName, asset.Name)
+}
+
+// downloadVerified downloads url to dst and checks its SHA-256 against want (skipped when want is "").
+// dst is removed if anything goes wrong.
+func downloadVerified(url, dst, want string) (err error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed: status %d", resp.StatusCode)
+	}
+
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); want != "" && got != want {
+		return fmt.Errorf("checksum mismatch: got %s, want %s", got, want)
+	}
+	return nil
+}
+
+// replaceExecutable moves the new binary over exe. Windows doesn't allow replacing a running
+// executable but does allow renaming it, so there the old binary is moved aside to exe.old first
+// and removed the next time ftc-helper starts.
+func replaceExecutable(exe, newPath, goos string) error {
+	if goos != "windows" {
+		return os.Rename(newPath, exe)
+	}
+	old := exe + ".old"
+	os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		return err
+	}
+	if err := os.Rename(newPath, exe); err != nil {
+		// Put the original back so the install isn't left without a binary.
+		if rerr := os.Rename(old, exe); rerr != nil {
+			return fmt.Errorf("%v (restoring the previous binary also failed: %v; it is at %s)", err, rerr, old)
+		}
+		return err
+	}
+	return nil
+}
+
+// removeOldExecutable deletes the binary left behind by a self-update on Windows.
+func removeOldExecutable() {
+	if runtime.GOOS != "windows" {
+		return
+	}
+	if exe, err := os.Executable(); err == nil {
+		os.Remove(exe + ".old")
+	}
+}
+
+// runningExecutable returns the resolved path of the running binary.
+func runningExecutable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
+
+// self-update: replace the running binary with a release from GitHub
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update ftc-helper to the latest release",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkOnly, _ := cmd.Flags().GetBool("check")
+		pinned, _ := cmd.Flags().GetString("version")
+		force, _ := cmd.Flags().GetBool("force")
+		noVerify, _ := cmd.Flags().GetBool("no-verify")
+
+		current, _ := getVersion()
+		release, err := fetchHelperRelease(pinned)
+		if err != nil {
+			fmt.Println("Error looking up release:", err)
+			return
+		}
+
+		fmt.Printf("Current version: %s\n", valueOr(current, "unknown"))
+		if pinned != "" {
+			fmt.Println("Requested release:", release.TagName)
+		} else {
+			fmt.Println("Latest release:", release.TagName)
+		}
+
+		// Without a pin only move forward; a build from source (commit hash) always counts as older.
+		upToDate := pinned == "" && isReleaseVersion(current) && compareVersions(current, release.TagName) >= 0
+		if pinned != "" && current == release.TagName {
+			upToDate = true
+		}
+		if checkOnly {
+			if upToDate {
+				fmt.Println("ftc-helper is up to date.")
+			} else {
+				fmt.Println("An update is available. Run 'ftc-helper self-update' to install", release.TagName)
+			}
+			return
+		}
+		if upToDate && !force {
+			fmt.Println("ftc-helper is up to date.")
+			return
+		}
+
+		name := helperAssetName(runtime.GOOS, runtime.GOARCH)
+		asset, ok := release.findAsset(name)
+		if !ok {
+			fmt.Printf("Release %s has no build for %s/%s (expected %s).\n", release.TagName, runtime.GOOS, runtime.GOARCH, name)
+			return
+		}
+
+		var sum string
+		if !noVerify {
+			sum, err = expectedChecksum(release, asset)
+			if err != nil {
+				fmt.Println("Error:", err)
+				fmt.Println("Use --no-verify to install without checking the download.")
+				return
+			}
+		}
+
+		exe, err := runningExecutable()
+		if err != nil {
+			fmt.Println("Error locating the running binary:", err)
+			return
+		}
+		// Download next to the binary so the final rename doesn't cross file systems.
+		newPath := exe + ".new"
+		fmt.Printf("Downloading %s...\n", asset.Name)
+		if err := downloadVerified(asset.DownloadURL, newPath, sum); err != nil {
+			fmt.Println("Error downloading update:", err)
+			return
+		}
+		if err := replaceExecutable(exe, newPath, runtime.GOOS); err != nil {
+			os.Remove(newPath)
+			if errors.Is(err, os.ErrPermission) {
+				fmt.Printf("Error: no permission to replace %s. Run the update as an administrator or reinstall manually.\n", exe)
+				return
+			}
+			fmt.Println("Error replacing binary:", err)
+			return
+		}
+		fmt.Printf("Updated ftc-helper to %s.\n", release.TagName)
+	},
+}
+
+var releaseVersion = regexp.MustCompile(`^v?\d+(\.\d+)+`)
+
+// isReleaseVersion reports whether v looks like a release tag such as v0.1.7 rather than a commit hash.
+func isReleaseVersion(v string) bool {
+	return releaseVersion.MatchString(v)
+}
+
+func init() {
+	selfUpdateCmd.Flags().Bool("check", false, "Only report whether an update is available")
+	selfUpdateCmd.Flags().String("version", "", "Install this release instead of the latest, e.g. v0.1.7")
+	selfUpdateCmd.Flags().Bool("force", false, "Reinstall even if already up to date")
+	selfUpdateCmd.Flags().Bool("no-verify", false, "Skip checksum verification")
+}
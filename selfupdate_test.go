@@ -0,0 +1,127 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHelperAssetName(t *testing.T) {
+	tests := map[[2]string]string{
+		{"linux", "amd64"}:   "ftc-helper-linux-amd64",
+		{"darwin", "arm64"}:  "ftc-helper-darwin-arm64",
+		{"windows", "amd64"}: "ftc-helper-windows-amd64.exe",
+	}
+	for in, want := range tests {
+		if got := helperAssetName(in[0], in[1]); got != want {
+			t.Errorf("helperAssetName(%s, %s) = %q, want %q", in[0], in[1], got, want)
+		}
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	input := sum + "  ftc-helper-linux-amd64\n" + strings.Repeat("CD", 32) + " *ftc-helper-windows-amd64.exe\nnot a checksum line\n"
+	got := parseChecksums(strings.NewReader(input))
+	if len(got) != 2 || got["ftc-helper-linux-amd64"] != sum || got["ftc-helper-windows-amd64.exe"] != strings.Repeat("cd", 32) {
+		t.Errorf("parseChecksums = %v", got)
+	}
+}
+
+func TestFetchHelperRelease_Pinned(t *testing.T) {
+	fakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/Harnish/ftc-helper/releases/tags/v0.1.7" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"tag_name":"v0.1.7","assets":[{"name":"ftc-helper-linux-amd64","browser_download_url":"http://x/bin"}]}`))
+	})
+	r, err := fetchHelperRelease("0.1.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, ok := r.findAsset("ftc-helper-linux-amd64"); !ok || a.DownloadURL != "http://x/bin" {
+		t.Errorf("release = %+v", r)
+	}
+}
+
+func TestExpectedChecksumAndDownload(t *testing.T) {
+	binary := []byte("new ftc-helper binary")
+	h := sha256.Sum256(binary)
+	sum := hex.EncodeToString(h[:])
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/checksums.txt":
+			fmt.Fprintf(w, "%s  ftc-helper-linux-amd64\n", sum)
+		case "/bin":
+			w.Write(binary)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	asset := ReleaseAsset{Name: "ftc-helper-linux-amd64", DownloadURL: srv.URL + "/bin"}
+	withSums := &HelperRelease{TagName: "v1.0.0", Assets: []ReleaseAsset{asset, {Name: checksumsAsset, DownloadURL: srv.URL + "/checksums.txt"}}}
+	if got, err := expectedChecksum(withSums, asset); err != nil || got != sum {
+		t.Errorf("checksums.txt: got %q, %v", got, err)
+	}
+	digest := asset
+	digest.Digest = "sha256:" + sum
+	if got, err := expectedChecksum(&HelperRelease{Assets: []ReleaseAsset{digest}}, digest); err != nil || got != sum {
+		t.Errorf("digest: got %q, %v", got, err)
+	}
+	if _, err := expectedChecksum(&HelperRelease{Assets: []ReleaseAsset{asset}}, asset); err == nil {
+		t.Error("expected error without any checksum")
+	}
+
+	dst := filepath.Join(t.TempDir(), "ftc-helper.new")
+	if err := downloadVerified(asset.DownloadURL, dst, sum); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(dst); string(b) != string(binary) {
+		t.Errorf("downloaded %q", b)
+	}
+	if err := downloadVerified(asset.DownloadURL, dst, strings.Repeat("0", 64)); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(dst); err == nil {
+		t.Error("bad download should be removed")
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	for _, goos := range []string{"linux", "windows"} {
+		t.Run(goos, func(t *testing.T) {
+			dir := t.TempDir()
+			exe := filepath.Join(dir, "ftc-helper")
+			os.WriteFile(exe, []byte("old"), 0755)
+			os.WriteFile(exe+".new", []byte("new"), 0755)
+
+			if err := replaceExecutable(exe, exe+".new", goos); err != nil {
+				t.Fatal(err)
+			}
+			if b, _ := os.ReadFile(exe); string(b) != "new" {
+				t.Errorf("binary = %q", b)
+			}
+			_, err := os.Stat(exe + ".old")
+			if (goos == "windows") != (err == nil) {
+				t.Errorf("old binary kept = %v", err == nil)
+			}
+		})
+	}
+}
+
+func TestIsReleaseVersion(t *testing.T) {
+	for v, want := range map[string]bool{"v0.1.7": true, "1.2": true, "a1b2c3d": false, "": false} {
+		if got := isReleaseVersion(v); got != want {
+			t.Errorf("isReleaseVersion(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
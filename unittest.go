@@ -0,0 +1,281 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const junitVersion = "4.13.2"
+
+// unitTestDir is where Gradle looks for JVM unit tests of the TeamCode module.
+var unitTestDir = filepath.Join("TeamCode", "src", "test", "java", "org", "firstinspires", "ftc", "teamcode")
+
+// testResultsDir is where testDebugUnitTest writes its JUnit XML reports.
+var testResultsDir = filepath.Join("TeamCode", "build", "test-results", "testDebugUnitTest")
+
+const exampleUnitTest = `package org.firstinspires.ftc.teamcode;
+
+import static org.junit.Assert.assertEquals;
+
+import org.junit.Test;
+
+/**
+ * Runs on the laptop with 'ftc-helper test', no robot needed. Put pure-Java logic such as
+ * PID controllers or kinematics in classes that don't use hardwareMap, and test them here.
+ */
+public class ExampleUnitTest {
+    @Test
+    public void addition() {
+        assertEquals(4, 2 + 2);
+    }
+}
+`
+
+// hasJUnit reports whether a Gradle script declares a JUnit test dependency.
+func hasJUnit(gradle string) bool {
+	for _, line := range strings.Split(gradle, "\n") {
+		line = stripGradleComment(line)
+		if strings.Contains(line, "testImplementation") && (strings.Contains(line, "junit:junit") || strings.Contains(line, "org.junit")) {
+			return true
+		}
+	}
+	return false
+}
+
+// addJUnitToGradle adds JUnit to TeamCode/build.gradle and makes android.jar stubs return defaults
+// instead of throwing, so tests can touch SDK classes that call into Android.
+func addJUnitToGradle(content string) (string, error) {
+	if hasJUnit(content) {
+		return content, nil
+	}
+	marker := gradleMarker + "junit"
+	content, err := appendToGradleBlock(content, "dependencies", []string{
+		fmt.Sprintf("    testImplementation 'junit:junit:%s' %s", junitVersion, marker),
+	})
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(content, "returnDefaultValues") {
+		return content, nil
+	}
+	return appendToGradleBlock(content, "android", []string{
+		"    testOptions.unitTests.returnDefaultValues = true " + marker,
+	})
+}
+
+// missingUnitTestSetup lists what the project lacks to run JVM unit tests.
+func missingUnitTestSetup(projectPath string) []string {
+	var missing []string
+	junit := false
+	for _, f := range []string{filepath.Join("TeamCode", "build.gradle"), "build.dependencies.gradle"} {
+		if b, err := os.ReadFile(filepath.Join(projectPath, f)); err == nil && hasJUnit(string(b)) {
+			junit = true
+		}
+	}
+	if !junit {
+		missing = append(missing, "JUnit testImplementation dependency in TeamCode/build.gradle")
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, unitTestDir)); err != nil {
+		missing = append(missing, "test source directory "+filepath.ToSlash(unitTestDir))
+	}
+	return missing
+}
+
+// setupUnitTests adds JUnit to TeamCode and creates the test source set with an example test.
+func setupUnitTests(projectPath string) error {
+	gradle := filepath.Join(projectPath, "TeamCode", "build.gradle")
+	if err := editGradleFile(gradle, addJUnitToGradle); err != nil {
+		return err
+	}
+	dir := filepath.Join(projectPath, unitTestDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) > 0 {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "ExampleUnitTest.java"), []byte(exampleUnitTest), 0644)
+}
+
+// junitSuite is the subset of the JUnit XML report format that Gradle writes.
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     float64     `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Class   string `xml:"classname,attr"`
+	Name    string `xml:"name,attr"`
+	Failure *struct {
+		Message string `xml:"message,attr"`
+	} `xml:"failure"`
+	Error *struct {
+		Message string `xml:"message,attr"`
+	} `xml:"error"`
+}
+
+// TestFailure is a failed or errored test case.
+type TestFailure struct {
+	Class   string
+	Name    string
+	Message string
+}
+
+// TestSummary totals the JUnit reports of a test run.
+type TestSummary struct {
+	Tests, Failures, Errors, Skipped int
+	Time                             float64
+	Failed                           []TestFailure
+}
+
+func (s TestSummary) passed() int {
+	return s.Tests - s.Failures - s.Errors - s.Skipped
+}
+
+// readTestResults parses every TEST-*.xml report in dir.
+func readTestResults(dir string) (TestSummary, error) {
+	var s TestSummary
+	files, err := filepath.Glob(filepath.Join(dir, "TEST-*.xml"))
+	if err != nil {
+		return s, err
+	}
+	if len(files) == 0 {
+		return s, fmt.Errorf("no test reports in %s", dir)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return s, err
+		}
+		var suite junitSuite
+		if err := xml.Unmarshal(b, &suite); err != nil {
+			return s, fmt.Errorf("%s: %w", filepath.Base(f), err)
+		}
+		s.Tests += suite.Tests
+		s.Failures += suite.Failures
+		s.Errors += suite.Errors
+		s.Skipped += suite.Skipped
+		s.Time += suite.Time
+		for _, c := range suite.Cases {
+			switch {
+			case c.Failure != nil:
+				s.Failed = append(s.Failed, TestFailure{c.Class, c.Name, c.Failure.Message})
+			case c.Error != nil:
+				s.Failed = append(s.Failed, TestFailure{c.Class, c.Name, c.Error.Message})
+			}
+		}
+	}
+	return s, nil
+}
+
+func printTestSummary(w io.Writer, s TestSummary) {
+	for _, f := range s.Failed {
+		class := f.Class
+		if i := strings.LastIndex(class, "."); i >= 0 {
+			class = class[i+1:]
+		}
+		fmt.Fprintf(w, "FAIL %s.%s\n", class, f.Name)
+		if f.Message != "" {
+			fmt.Fprintf(w, "     %s\n", strings.SplitN(f.Message, "\n", 2)[0])
+		}
+	}
+	fmt.Fprintf(w, "%d test(s): %d passed, %d failed, %d skipped (%.1fs)\n",
+		s.Tests, s.passed(), s.Failures+s.Errors, s.Skipped, s.Time)
+}
+
+// runGradle runs the project's Gradle wrapper with the output streamed to the terminal. A variable so
+// tests don't need a JDK.
+var runGradle = func(projectPath string, args ...string) error {
+	wrapper := filepath.Join(projectPath, "gradlew")
+	if runtime.GOOS == "windows" {
+		wrapper += ".bat"
+	}
+	if _, err := os.Stat(wrapper); err != nil {
+		return fmt.Errorf("no Gradle wrapper in %s", projectPath)
+	}
+	cmd := exec.Command(wrapper, args...)
+	cmd.Dir = projectPath
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if home, err := javaHome(); err == nil {
+		cmd.Env = append(cmd.Env, "JAVA_HOME="+home)
+	}
+	return cmd.Run()
+}
+
+// test: run TeamCode unit tests on the laptop
+var testCmd = &cobra.Command{
+	Use:   "test [project_name]",
+	Short: "Run the TeamCode JVM unit tests with Gradle",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setup, _ := cmd.Flags().GetBool("setup")
+		filter, _ := cmd.Flags().GetString("tests")
+		projectPath, err := existingProject(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if missing := missingUnitTestSetup(projectPath); len(missing) > 0 {
+			if !setup {
+				fmt.Println("The project is not set up for unit tests. Missing:")
+				for _, m := range missing {
+					fmt.Println("  -", m)
+				}
+				fmt.Printf("Run 'ftc-helper test %s --setup' to add them.\n", args[0])
+				os.Exit(1)
+			}
+			if err := setupUnitTests(projectPath); err != nil {
+				fmt.Println("Error setting up unit tests:", err)
+				os.Exit(1)
+			}
+			fmt.Println("Added JUnit and", filepath.ToSlash(unitTestDir))
+		}
+
+		// Old reports would otherwise be summarized when the build fails before any test runs.
+		os.RemoveAll(filepath.Join(projectPath, testResultsDir))
+		gradleArgs := []string{":TeamCode:testDebugUnitTest"}
+		if filter != "" {
+			gradleArgs = append(gradleArgs, "--tests", filter)
+		}
+		gradleErr := runGradle(projectPath, gradleArgs...)
+
+		summary, err := readTestResults(filepath.Join(projectPath, testResultsDir))
+		if err != nil {
+			if gradleErr != nil {
+				fmt.Println("Error running Gradle:", gradleErr)
+			} else {
+				fmt.Println("Error:", err)
+			}
+			os.Exit(1)
+		}
+		fmt.Println()
+		printTestSummary(os.Stdout, summary)
+		if gradleErr != nil || len(summary.Failed) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	testCmd.Flags().Bool("setup", false, "Add JUnit and the test source directory to TeamCode if they are missing")
+	testCmd.Flags().String("tests", "", "Only run tests matching this Gradle filter, e.g. '*PidTest'")
+}
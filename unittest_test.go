@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const teamCodeGradle = `apply from: '../build.common.gradle'
+apply from: '../build.dependencies.gradle'
+
+android {
+    namespace = 'org.firstinspires.ftc.teamcode'
+}
+
+dependencies {
+    implementation project(':FtcRobotController')
+}
+`
+
+func TestSetupUnitTests(t *testing.T) {
+	root := makeTestProject(t, t.TempDir(), "robot")
+	os.WriteFile(filepath.Join(root, "TeamCode", "build.gradle"), []byte(teamCodeGradle), 0644)
+
+	if missing := missingUnitTestSetup(root); len(missing) != 2 {
+		t.Fatalf("missing = %v, want JUnit and test dir", missing)
+	}
+	if err := setupUnitTests(root); err != nil {
+		t.Fatal(err)
+	}
+	if missing := missingUnitTestSetup(root); len(missing) != 0 {
+		t.Errorf("still missing %v after setup", missing)
+	}
+
+	b, _ := os.ReadFile(filepath.Join(root, "TeamCode", "build.gradle"))
+	gradle := string(b)
+	for _, want := range []string{"testImplementation 'junit:junit:" + junitVersion + "'", "testOptions.unitTests.returnDefaultValues = true"} {
+		if strings.Count(gradle, want) != 1 {
+			t.Errorf("build.gradle should contain %q once:\n%s", want, gradle)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, unitTestDir, "ExampleUnitTest.java")); err != nil {
+		t.Errorf("example test not created: %v", err)
+	}
+
+	// Running it again changes nothing.
+	if err := setupUnitTests(root); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := os.ReadFile(filepath.Join(root, "TeamCode", "build.gradle")); string(again) != gradle {
+		t.Errorf("second setup changed build.gradle:\n%s", again)
+	}
+}
+
+func TestHasJUnit(t *testing.T) {
+	tests := []struct {
+		gradle string
+		want   bool
+	}{
+		{"testImplementation 'junit:junit:4.13.2'", true},
+		{"testImplementation 'org.junit.jupiter:junit-jupiter:5.10.0'", true},
+		{"// testImplementation 'junit:junit:4.13.2'", false},
+		{"implementation 'org.firstinspires.ftc:RobotCore:10.1.0'", false},
+	}
+	for _, tt := range tests {
+		if got := hasJUnit(tt.gradle); got != tt.want {
+			t.Errorf("hasJUnit(%q) = %v, want %v", tt.gradle, got, tt.want)
+		}
+	}
+}
+
+func TestReadTestResults(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "TEST-org.firstinspires.ftc.teamcode.PidTest.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="org.firstinspires.ftc.teamcode.PidTest" tests="3" skipped="1" failures="1" errors="0" time="0.25">
+  <testcase name="settles" classname="org.firstinspires.ftc.teamcode.PidTest" time="0.1"/>
+  <testcase name="overshoot" classname="org.firstinspires.ftc.teamcode.PidTest" time="0.1">
+    <failure message="expected:&lt;1.0&gt; but was:&lt;1.3&gt;" type="java.lang.AssertionError">stack</failure>
+  </testcase>
+  <testcase name="later" classname="org.firstinspires.ftc.teamcode.PidTest" time="0"><skipped/></testcase>
+</testsuite>`), 0644)
+	os.WriteFile(filepath.Join(dir, "TEST-org.firstinspires.ftc.teamcode.KinematicsTest.xml"), []byte(`<testsuite name="KinematicsTest" tests="2" skipped="0" failures="0" errors="1" time="0.5">
+  <testcase name="forward" classname="org.firstinspires.ftc.teamcode.KinematicsTest"/>
+  <testcase name="strafe" classname="org.firstinspires.ftc.teamcode.KinematicsTest"><error message="NullPointerException"/></testcase>
+</testsuite>`), 0644)
+
+	s, err := readTestResults(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Tests != 5 || s.passed() != 2 || len(s.Failed) != 2 {
+		t.Errorf("summary = %+v", s)
+	}
+
+	var buf bytes.Buffer
+	printTestSummary(&buf, s)
+	for _, want := range []string{"FAIL PidTest.overshoot", "expected:<1.0> but was:<1.3>", "FAIL KinematicsTest.strafe", "5 test(s): 2 passed, 2 failed, 1 skipped (0.8s)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("summary output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	if _, err := readTestResults(t.TempDir()); err == nil {
+		t.Error("expected error for a directory without reports")
+	}
+}
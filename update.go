@@ -0,0 +1,325 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// UpdateState caches the result of the last update check so it runs at most once per interval.
+type UpdateState struct {
+	Checked      time.Time `json:"checked"`
+	LatestSDK    string    `json:"latest_sdk,omitempty"`
+	LatestStudio string    `json:"latest_studio,omitempty"`
+	LatestREV    string    `json:"latest_rev,omitempty"`
+	// InstalledREV is the REV Hardware Client version last downloaded with download-rev, empty if
+	// unknown. The client's install location varies too much to detect it.
+	InstalledREV string `json:"installed_rev,omitempty"`
+	// Error describes what went wrong during the last check, if anything.
+	Error string `json:"error,omitempty"`
+}
+
+func updateStateFile() string {
+	return filepath.Join(cacheDir(), "update-check.json")
+}
+
+// loadUpdateState reads the cached state. A missing or corrupt file yields an empty state.
+func loadUpdateState() UpdateState {
+	var s UpdateState
+	if b, err := os.ReadFile(updateStateFile()); err == nil {
+		json.Unmarshal(b, &s)
+	}
+	return s
+}
+
+func saveUpdateState(s UpdateState) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(updateStateFile(), b, 0644)
+}
+
+// updateCheckInterval is how long a check result is reused (update_check_interval hours, default 24).
+func updateCheckInterval() time.Duration {
+	if h := viper.GetInt("update_check_interval"); h > 0 {
+		return time.Duration(h) * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// updateSources looks up the latest and installed versions. Fields are functions so tests can fake them.
+type updateSources struct {
+	latestSDK       func() (string, error)
+	latestStudio    func() (string, error)
+	latestREV       func() (string, error)
+	installedStudio func() (string, error)
+}
+
+var defaultUpdateSources = updateSources{
+	latestSDK: latestRelease,
+	latestStudio: func() (string, error) {
+		u, err := findLatestAndroidStudioURL(detectStudioPlatform())
+		if err != nil {
+			return "", err
+		}
+		return versionInString(u, studioVersion)
+	},
+	latestREV: func() (string, error) {
+		_, filename, err := findRevHardwareClientURL()
+		if err != nil {
+			return "", err
+		}
+		return versionInString(filename, dottedVersion)
+	},
+	installedStudio: DetectAndroidStudioVersion,
+}
+
+var (
+	studioVersion = regexp.MustCompile(`\d{4}\.\d+(?:\.\d+)*`)
+	dottedVersion = regexp.MustCompile(`\d+\.\d+(?:\.\d+)*`)
+)
+
+func versionInString(s string, re *regexp.Regexp) (string, error) {
+	if v := re.FindString(s); v != "" {
+		return v, nil
+	}
+	return "", fmt.Errorf("no version found in %s", path.Base(s))
+}
+
+// refreshUpdateState queries every source and records the results. Sources that fail keep their
+// previous value, so a flaky scrape doesn't hide a known update.
+func refreshUpdateState(s *UpdateState, src updateSources, now time.Time) []error {
+	var errs []error
+	for _, f := range []struct {
+		name   string
+		lookup func() (string, error)
+		dst    *string
+	}{
+		{"FtcRobotController", src.latestSDK, &s.LatestSDK},
+		{"Android Studio", src.latestStudio, &s.LatestStudio},
+		{"REV Hardware Client", src.latestREV, &s.LatestREV},
+	} {
+		v, err := f.lookup()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.name, err))
+			continue
+		}
+		*f.dst = v
+	}
+	s.Error = ""
+	if len(errs) > 0 {
+		s.Error = errors.Join(errs...).Error()
+	}
+	s.Checked = now
+	return errs
+}
+
+// UpdateNotice describes one component with a newer release.
+type UpdateNotice struct {
+	Component, Current, Latest, Hint string
+}
+
+// pendingUpdates compares the cached latest versions with what is in use. projectSDK and installedStudio
+// may be empty when unknown, in which case that component is skipped.
+func pendingUpdates(s UpdateState, projectSDK, installedStudio string) []UpdateNotice {
+	var notices []UpdateNotice
+	if projectSDK != "" && s.LatestSDK != "" && compareVersions(projectSDK, s.LatestSDK) < 0 {
+		notices = append(notices, UpdateNotice{"FtcRobotController SDK", projectSDK, s.LatestSDK, "see 'ftc-helper sdk diff " + projectSDK + " " + s.LatestSDK + "'"})
+	}
+	if installed, err := versionInString(installedStudio, studioVersion); err == nil && s.LatestStudio != "" {
+		// product-info.json often only has "2024.2"; compare as many components as are installed.
+		latest := strings.Split(s.LatestStudio, ".")
+		if n := len(strings.Split(installed, ".")); n < len(latest) {
+			latest = latest[:n]
+		}
+		if compareVersions(installed, strings.Join(latest, ".")) < 0 {
+			notices = append(notices, UpdateNotice{"Android Studio", installed, s.LatestStudio, "run 'ftc-helper download-studio'"})
+		}
+	}
+	if s.InstalledREV != "" && s.LatestREV != "" && compareVersions(s.InstalledREV, s.LatestREV) < 0 {
+		notices = append(notices, UpdateNotice{"REV Hardware Client", s.InstalledREV, s.LatestREV, "run 'ftc-helper download-rev'"})
+	}
+	return notices
+}
+
+// currentProject returns the root of the project containing the working directory, if any.
+func currentProject() (string, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "TeamCode")); err == nil {
+			if _, err := os.Stat(filepath.Join(dir, "build.dependencies.gradle")); err == nil {
+				return dir, true
+			}
+			if _, err := os.Stat(filepath.Join(dir, manifestFile)); err == nil {
+				return dir, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// updateCheckSkipped lists commands that never print the update notice.
+var updateCheckSkipped = map[string]bool{
+	"check-updates": true,
+	"completion":    true,
+	"help":          true,
+	"version":       true,
+	"self-update":   true,
+}
+
+// updateCheckTimeout bounds the automatic check. A variable so tests don't wait for it.
+var updateCheckTimeout = 5 * time.Second
+
+// notifyUpdates prints a one-line notice after a command when update_check is enabled and something is
+// out of date. The network is only used once per update_check_interval, whether or not the check
+// succeeds, and never for longer than a few seconds, so it doesn't slow down day-to-day commands.
+func notifyUpdates(cmd *cobra.Command) {
+	if !viper.GetBool("update_check") || updateCheckSkipped[cmd.Name()] {
+		return
+	}
+
+	state := loadUpdateState()
+	if time.Since(state.Checked) > updateCheckInterval() {
+		done := make(chan UpdateState, 1)
+		go func(s UpdateState) {
+			refreshUpdateState(&s, defaultUpdateSources, time.Now())
+			done <- s
+		}(state)
+		select {
+		case state = <-done:
+			saveUpdateState(state)
+		case <-time.After(updateCheckTimeout):
+			// Record the attempt anyway, so an offline laptop doesn't wait on every command.
+			state.Checked = time.Now()
+			state.Error = fmt.Sprintf("timed out after %s", updateCheckTimeout)
+			saveUpdateState(state)
+			return
+		}
+	}
+
+	var projectSDK string
+	if p, ok := currentProject(); ok {
+		projectSDK, _, _ = detectSDKVersion(p)
+	}
+	installedStudio, _ := DetectAndroidStudioVersion()
+	if line := updateNoticeLine(pendingUpdates(state, projectSDK, installedStudio)); line != "" {
+		fmt.Fprintln(os.Stderr, line)
+	}
+}
+
+// updateNoticeLine summarises notices on one line, or returns "" if there are none.
+func updateNoticeLine(notices []UpdateNotice) string {
+	if len(notices) == 0 {
+		return ""
+	}
+	parts := make([]string, len(notices))
+	for i, n := range notices {
+		parts[i] = fmt.Sprintf("%s %s (you have %s)", n.Component, n.Latest, n.Current)
+	}
+	return "Update available: " + strings.Join(parts, ", ") + ". Run 'ftc-helper check-updates' for details."
+}
+
+// recordREVDownload marks the latest REV Hardware Client as installed after download-rev.
+func recordREVDownload(filename string) {
+	v, err := versionInString(filename, dottedVersion)
+	if err != nil {
+		return
+	}
+	state := loadUpdateState()
+	state.InstalledREV = v
+	if compareVersions(v, state.LatestREV) > 0 {
+		state.LatestREV = v
+	}
+	saveUpdateState(state)
+}
+
+func printUpdateReport(w io.Writer, s UpdateState, projectName, projectSDK, installedStudio string) {
+	current := func(v string) string { return valueOr(v, "unknown") }
+	latest := func(v string) string { return valueOr(v, "unknown") }
+
+	fmt.Fprintf(w, "%-24s %-20s %-20s\n", "COMPONENT", "CURRENT", "LATEST")
+	sdkLabel := "FtcRobotController SDK"
+	if projectName != "" {
+		sdkLabel = "SDK (" + projectName + ")"
+	}
+	fmt.Fprintf(w, "%-24s %-20s %-20s\n", sdkLabel, current(projectSDK), latest(s.LatestSDK))
+	fmt.Fprintf(w, "%-24s %-20s %-20s\n", "Android Studio", current(installedStudio), latest(s.LatestStudio))
+	fmt.Fprintf(w, "%-24s %-20s %-20s\n", "REV Hardware Client", current(s.InstalledREV), latest(s.LatestREV))
+
+	notices := pendingUpdates(s, projectSDK, installedStudio)
+	if len(notices) == 0 {
+		fmt.Fprintln(w, "\nEverything is up to date.")
+		return
+	}
+	fmt.Fprintln(w)
+	for _, n := range notices {
+		fmt.Fprintf(w, "%s %s is available: %s\n", n.Component, n.Latest, n.Hint)
+	}
+}
+
+// check-updates: report available SDK and tool updates
+var checkUpdatesCmd = &cobra.Command{
+	Use:   "check-updates [project_name]",
+	Short: "Check for newer FTC SDK, Android Studio and REV Hardware Client releases",
+	Long: `Check for newer FTC SDK, Android Studio and REV Hardware Client releases.
+
+The SDK is compared with the given project, or the project containing the
+current directory. Set update_check to true to be notified automatically.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var projectPath, projectName string
+		if len(args) == 1 {
+			p, err := existingProject(args[0])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			projectPath, projectName = p, args[0]
+		} else if p, ok := currentProject(); ok {
+			projectPath, projectName = p, filepath.Base(p)
+		}
+
+		state := loadUpdateState()
+		fmt.Println("Checking for updates...")
+		for _, err := range refreshUpdateState(&state, defaultUpdateSources, time.Now()) {
+			fmt.Println("Warning:", err)
+		}
+		if err := saveUpdateState(state); err != nil {
+			fmt.Println("Warning: could not cache the result:", err)
+		}
+
+		var projectSDK string
+		if projectPath != "" {
+			projectSDK, _, _ = detectSDKVersion(projectPath)
+		}
+		installedStudio, _ := DetectAndroidStudioVersion()
+		if v, err := versionInString(installedStudio, studioVersion); err == nil {
+			installedStudio = v
+		}
+		printUpdateReport(os.Stdout, state, projectName, projectSDK, installedStudio)
+		if !viper.GetBool("update_check") {
+			fmt.Println("\nTip: run 'ftc-helper config set update_check true' to be notified automatically.")
+		}
+	},
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func fakeUpdateSources(sdk, studio, rev string) updateSources {
+	value := func(v string) func() (string, error) {
+		return func() (string, error) {
+			if v == "" {
+				return "", errors.New("offline")
+			}
+			return v, nil
+		}
+	}
+	return updateSources{latestSDK: value(sdk), latestStudio: value(studio), latestREV: value(rev), installedStudio: value("")}
+}
+
+func TestRefreshUpdateState(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	var s UpdateState
+	if errs := refreshUpdateState(&s, fakeUpdateSources("v10.1", "2024.2.1.11", "1.6.1"), now); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if s.LatestSDK != "v10.1" || s.LatestStudio != "2024.2.1.11" || s.LatestREV != "1.6.1" || s.InstalledREV != "" || s.Error != "" || !s.Checked.Equal(now) {
+		t.Errorf("state = %+v", s)
+	}
+
+	// A failing source keeps the last known value.
+	errs := refreshUpdateState(&s, fakeUpdateSources("v10.2", "", "1.7.0"), now)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "Android Studio") {
+		t.Errorf("errs = %v", errs)
+	}
+	if s.LatestSDK != "v10.2" || s.LatestStudio != "2024.2.1.11" || s.LatestREV != "1.7.0" || !strings.Contains(s.Error, "Android Studio") {
+		t.Errorf("state = %+v", s)
+	}
+}
+
+func TestPendingUpdates(t *testing.T) {
+	s := UpdateState{LatestSDK: "v10.2", LatestStudio: "2024.2.1.11", LatestREV: "1.7.0", InstalledREV: "1.6.1"}
+	tests := []struct {
+		name                        string
+		state                       UpdateState
+		projectSDK, installedStudio string
+		want                        []string
+	}{
+		{"all outdated", s, "v10.1", "Android Studio Koala | 2024.1.1", []string{"FtcRobotController SDK", "Android Studio", "REV Hardware Client"}},
+		{"short studio version is current", s, "v10.2", "2024.2", []string{"REV Hardware Client"}},
+		{"unknown versions skipped", UpdateState{LatestSDK: "v10.2", LatestStudio: "2024.2.1.11"}, "", "", nil},
+		{"up to date", UpdateState{LatestSDK: "v10.2", LatestREV: "1.7.0", InstalledREV: "1.7.0"}, "v10.2", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, n := range pendingUpdates(tt.state, tt.projectSDK, tt.installedStudio) {
+				got = append(got, n.Component)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("pendingUpdates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateNoticeLine(t *testing.T) {
+	if updateNoticeLine(nil) != "" {
+		t.Error("expected no notice")
+	}
+	line := updateNoticeLine([]UpdateNotice{{Component: "FtcRobotController SDK", Current: "v10.1", Latest: "v10.2"}, {Component: "REV Hardware Client", Current: "1.6.1", Latest: "1.7.0"}})
+	want := "Update available: FtcRobotController SDK v10.2 (you have v10.1), REV Hardware Client 1.7.0 (you have 1.6.1). Run 'ftc-helper check-updates' for details."
+	if line != want {
+		t.Errorf("updateNoticeLine = %q, want %q", line, want)
+	}
+}
+
+func TestUpdateStateRoundTrip(t *testing.T) {
+	viper.Set("cache_dir", t.TempDir())
+	defer viper.Set("cache_dir", "")
+
+	if s := loadUpdateState(); !s.Checked.IsZero() {
+		t.Errorf("missing state = %+v", s)
+	}
+	want := UpdateState{Checked: time.Now().UTC().Truncate(time.Second), LatestSDK: "v10.1"}
+	if err := saveUpdateState(want); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadUpdateState(); !got.Checked.Equal(want.Checked) || got.LatestSDK != want.LatestSDK {
+		t.Errorf("loadUpdateState = %+v, want %+v", got, want)
+	}
+
+	recordREVDownload("REV-Hardware-Client-Setup-1.7.0.exe")
+	if got := loadUpdateState(); got.InstalledREV != "1.7.0" || got.LatestREV != "1.7.0" {
+		t.Errorf("after download = %+v", got)
+	}
+}
+
+func TestNotifyUpdates_TimeoutIsRecorded(t *testing.T) {
+	viper.Set("cache_dir", t.TempDir())
+	viper.Set("update_check", true)
+	defer viper.Set("cache_dir", "")
+	defer viper.Set("update_check", false)
+
+	block := make(chan struct{})
+	defer close(block)
+	slow := func() (string, error) { <-block; return "", errors.New("offline") }
+	defer func(orig updateSources, timeout time.Duration) {
+		defaultUpdateSources, updateCheckTimeout = orig, timeout
+	}(defaultUpdateSources, updateCheckTimeout)
+	defaultUpdateSources = updateSources{latestSDK: slow, latestStudio: slow, latestREV: slow, installedStudio: slow}
+	updateCheckTimeout = 10 * time.Millisecond
+
+	notifyUpdates(&cobra.Command{Use: "projects"})
+	s := loadUpdateState()
+	if s.Checked.IsZero() || !strings.Contains(s.Error, "timed out") {
+		t.Fatalf("state after timeout = %+v", s)
+	}
+}
@@ -2,9 +2,9 @@ package main
 
 import (
 	"fmt"
-	"io/ioutil"
-	"os/exec"
-	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -13,47 +13,105 @@ import (
 // Version can be set at build time with -ldflags "-X main.Version=..."
 var Version = ""
 
+// BuildDetails describes how the running binary was built.
+type BuildDetails struct {
+	Version   string
+	Revision  string
+	Dirty     bool
+	BuildTime string
+	GoVersion string
+}
+
+// buildDetails reads the build information embedded by the Go toolchain. It only describes the
+// binary itself, so running ftc-helper inside another git repository doesn't change the result.
+func buildDetails(info *debug.BuildInfo, ok bool) BuildDetails {
+	d := BuildDetails{Version: Version, GoVersion: runtime.Version()}
+	if !ok || info == nil {
+		return d
+	}
+	d.GoVersion = info.GoVersion
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			d.Revision = s.Value
+		case "vcs.modified":
+			d.Dirty = s.Value == "true"
+		case "vcs.time":
+			d.BuildTime = s.Value
+		}
+	}
+
+	if v := info.Main.Version; d.Version == "" && v != "" && v != "(devel)" && !devVersion(v) {
+		// Installed with `go install github.com/Harnish/ftc-helper@vX.Y.Z`.
+		d.Version = v
+	}
+	if d.Version == "" && d.Revision != "" {
+		rev := d.Revision
+		if len(rev) > 12 {
+			rev = rev[:12]
+		}
+		d.Version = "devel-" + rev
+		if d.Dirty {
+			d.Version += "-dirty"
+		}
+	}
+	return d
+}
+
+// pseudoVersion matches the timestamp and revision that end a Go pseudo-version, as in
+// v0.0.0-20261018175533-6f5b8a05df82 or v0.1.8-0.20261018175533-6f5b8a05df82.
+var pseudoVersion = regexp.MustCompile(`[-.]\d{14}-[0-9a-f]{12}$`)
+
+// devVersion reports whether a module version was stamped by the toolchain for a source build: Go 1.24
+// and later use a pseudo-version for untagged commits and add +dirty for uncommitted changes.
+func devVersion(v string) bool {
+	return strings.HasSuffix(v, "+dirty") || pseudoVersion.MatchString(v)
+}
+
+func currentBuild() BuildDetails {
+	return buildDetails(debug.ReadBuildInfo())
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the current ftc-helper version",
 	Run: func(cmd *cobra.Command, args []string) {
-		v, err := getVersion()
-		if err != nil {
-			fmt.Println("Version: unknown")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		d := currentBuild()
+		fmt.Println(valueOr(d.Version, "Version: unknown"))
+		if !verbose {
 			return
 		}
-		fmt.Println(v)
+
+		revision := valueOr(d.Revision, "unknown")
+		if d.Dirty {
+			revision += " (modified)"
+		}
+		fmt.Println("Commit:        ", revision)
+		fmt.Println("Commit time:   ", valueOr(d.BuildTime, "unknown"))
+		fmt.Println("Go version:    ", d.GoVersion)
+		fmt.Println("OS/Arch:       ", runtime.GOOS+"/"+runtime.GOARCH)
+		gitVersion, err := DetectGitVersion()
+		if err != nil {
+			gitVersion = "not found"
+		}
+		fmt.Println("Git:           ", gitVersion)
+		studio, err := DetectAndroidStudioVersion()
+		if err != nil {
+			studio = "not found"
+		}
+		fmt.Println("Android Studio:", studio)
 	},
 }
 
+// getVersion returns the version of the running binary.
 func getVersion() (string, error) {
-	// 1) build-time version
-	if Version != "" {
-		return Version, nil
+	if v := currentBuild().Version; v != "" {
+		return v, nil
 	}
-
-	// 2) filesystem VERSION file at repo root
-	rootOut, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
-	if err == nil {
-		root := strings.TrimSpace(string(rootOut))
-		verPath := filepath.Join(root, "VERSION")
-		if b, err := ioutil.ReadFile(verPath); err == nil {
-			s := strings.TrimSpace(string(b))
-			if s != "" {
-				return s, nil
-			}
-		}
-	}
-
-	// 3) try to get latest tag
-	if out, err := exec.Command("git", "describe", "--tags", "--abbrev=0").Output(); err == nil {
-		return strings.TrimSpace(string(out)), nil
-	}
-
-	// 4) fallback to commit
-	if out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output(); err == nil {
-		return strings.TrimSpace(string(out)), nil
-	}
-
 	return "", fmt.Errorf("could not determine version")
 }
+
+func init() {
+	versionCmd.Flags().BoolP("verbose", "v", false, "Also show build, Go, OS, git and Android Studio details")
+}
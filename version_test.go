@@ -0,0 +1,45 @@
+package main
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestBuildDetails(t *testing.T) {
+	settings := []debug.BuildSetting{
+		{Key: "vcs.revision", Value: "4d30bdf832292f9b90d8941c427845b08b1dee59"},
+		{Key: "vcs.modified", Value: "true"},
+		{Key: "vcs.time", Value: "2025-10-18T17:03:15Z"},
+	}
+	tests := []struct {
+		name    string
+		ldflags string
+		info    *debug.BuildInfo
+		want    string
+	}{
+		{"ldflags win", "v0.2.0", &debug.BuildInfo{Main: debug.Module{Version: "v0.1.0"}, Settings: settings}, "v0.2.0"},
+		{"go install", "", &debug.BuildInfo{Main: debug.Module{Version: "v0.1.0"}, Settings: settings}, "v0.1.0"},
+		{"source build", "", &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}, Settings: settings}, "devel-4d30bdf83229-dirty"},
+		{"go 1.24 source build", "", &debug.BuildInfo{Main: debug.Module{Version: "v0.0.0-20251018170315-4d30bdf83229+dirty"}, Settings: settings}, "devel-4d30bdf83229-dirty"},
+		{"go 1.24 clean source build", "", &debug.BuildInfo{Main: debug.Module{Version: "v0.1.8-0.20251018170315-4d30bdf83229"}, Settings: settings[:1]}, "devel-4d30bdf83229"},
+		{"go 1.24 dirty tag", "", &debug.BuildInfo{Main: debug.Module{Version: "v0.1.7+dirty"}, Settings: settings}, "devel-4d30bdf83229-dirty"},
+		{"no vcs", "", &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := Version
+			Version = tt.ldflags
+			defer func() { Version = old }()
+
+			d := buildDetails(tt.info, true)
+			if d.Version != tt.want {
+				t.Errorf("Version = %q, want %q", d.Version, tt.want)
+			}
+		})
+	}
+
+	d := buildDetails(&debug.BuildInfo{GoVersion: "go1.22.0", Settings: settings}, true)
+	if d.Revision != settings[0].Value || !d.Dirty || d.BuildTime != "2025-10-18T17:03:15Z" || d.GoVersion != "go1.22.0" {
+		t.Errorf("buildDetails = %+v", d)
+	}
+}
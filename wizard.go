@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxWizardReleases is how many releases the wizard offers to pick from.
+const maxWizardReleases = 10
+
+// isInteractive reports whether stdin is a terminal.
+func isInteractive() bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// initWizard asks the questions needed to build initOptions.
+type initWizard struct {
+	in       *bufio.Reader
+	out      io.Writer
+	releases []Release
+	now      time.Time
+	profile  TeamProfile
+	// checkRemote verifies a remote is reachable; nil skips the check.
+	checkRemote func(string) error
+}
+
+// runInitWizard fetches the release list and walks the user through creating a project.
+// It returns nil options if the user cancels at the summary.
+func runInitWizard(in io.Reader, out io.Writer) (*initOptions, error) {
+	fmt.Fprintln(out, "Fetching FTC releases...")
+	releases, err := fetchReleases()
+	if err != nil {
+		return nil, fmt.Errorf("fetching releases: %w", err)
+	}
+	w := &initWizard{
+		in:          bufio.NewReader(in),
+		out:         out,
+		releases:    releases,
+		now:         time.Now(),
+		profile:     teamProfile(),
+		checkRemote: checkRemoteReachable,
+	}
+	return w.run()
+}
+
+func (w *initWizard) run() (*initOptions, error) {
+	if len(w.releases) == 0 {
+		return nil, errors.New("no FTC releases found")
+	}
+	opts := &initOptions{}
+
+	// Release
+	fmt.Fprintln(w.out, "\nAvailable FTC releases:")
+	n := len(w.releases)
+	if n > maxWizardReleases {
+		n = maxWizardReleases
+	}
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(w.out, "  %d) %s\n", i+1, w.releases[i].TagName)
+	}
+	defaultRelease := w.releases[0].TagName
+	if v := w.resolveRelease(w.profile.SDKVersion); w.profile.SDKVersion != "" && v != "" {
+		defaultRelease = v
+	}
+	for opts.Version == "" {
+		answer, err := w.ask("Release (number or tag)", defaultRelease)
+		if err != nil {
+			return nil, err
+		}
+		opts.Version = w.resolveRelease(answer)
+		if opts.Version == "" {
+			fmt.Fprintln(w.out, "Unknown release:", answer)
+		}
+	}
+
+	// Project name
+	team, err := w.ask("Team number (optional)", w.profile.Number)
+	if err != nil {
+		return nil, err
+	}
+	defaultSeason := w.profile.Season
+	if defaultSeason == "" {
+		defaultSeason = strconv.Itoa(ftcSeason(w.now))
+	}
+	season, err := w.ask("Season", defaultSeason)
+	if err != nil {
+		return nil, err
+	}
+	for opts.ProjectName == "" {
+		name, err := w.ask("Project name", suggestProjectName(team, season))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(filepath.Join(workDir, name)); err == nil {
+			fmt.Fprintf(w.out, "%s already exists in %s, pick another name.\n", name, workDir)
+			continue
+		}
+		opts.ProjectName = name
+	}
+
+	// Git remote
+	for {
+		remote, err := w.ask("Git remote URL (optional)", "")
+		if err != nil {
+			return nil, err
+		}
+		if remote == "" {
+			break
+		}
+		r, warnings, err := ParseRemoteURL(remote)
+		if err != nil {
+			fmt.Fprintln(w.out, "Error:", err)
+			continue
+		}
+		for _, msg := range warnings {
+			fmt.Fprintln(w.out, "Warning:", msg)
+		}
+		if w.checkRemote != nil {
+			fmt.Fprintln(w.out, "Checking remote...")
+			if err := w.checkRemote(r.String()); err != nil {
+				fmt.Fprintln(w.out, "Error:", err)
+				continue
+			}
+		}
+		opts.GitURL = r.String()
+		break
+	}
+
+	// Language
+	defaultLanguage := w.profile.Language
+	if defaultLanguage == "" {
+		defaultLanguage = "java"
+	}
+	for opts.Language == "" {
+		lang, err := w.ask("Language (java/kotlin)", defaultLanguage)
+		if err != nil {
+			return nil, err
+		}
+		lang = strings.ToLower(lang)
+		if lang != "java" && lang != "kotlin" {
+			fmt.Fprintln(w.out, "Please answer java or kotlin.")
+			continue
+		}
+		opts.Language = lang
+	}
+
+	// Libraries
+	catalog, err := libraryCatalog()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(w.out, "\nOptional libraries:")
+	for _, l := range catalog {
+		fmt.Fprintf(w.out, "  %-14s %s\n", l.Name, l.Description)
+	}
+	for {
+		answer, err := w.ask("Libraries (comma separated, optional)", "")
+		if err != nil {
+			return nil, err
+		}
+		libs, err := parseLibraryList(answer)
+		if err != nil {
+			fmt.Fprintln(w.out, "Error:", err)
+			continue
+		}
+		opts.Libraries = libs
+		break
+	}
+
+	// Summary
+	fmt.Fprintln(w.out, "\nSummary:")
+	fmt.Fprintln(w.out, "  Release:   ", opts.Version)
+	fmt.Fprintln(w.out, "  Project:   ", filepath.Join(workDir, opts.ProjectName))
+	fmt.Fprintln(w.out, "  Git remote:", valueOrNone(opts.GitURL))
+	fmt.Fprintln(w.out, "  Language:  ", opts.Language)
+	fmt.Fprintln(w.out, "  Libraries: ", valueOrNone(strings.Join(opts.Libraries, ", ")))
+	ok, err := w.ask("Create project? (Y/n)", "y")
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(strings.ToLower(ok), "y") {
+		return nil, nil
+	}
+	return opts, nil
+}
+
+// ask prints a prompt and returns the trimmed answer, or def if the answer is empty.
+func (w *initWizard) ask(prompt, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", prompt, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", prompt)
+	}
+	line, err := w.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		if err == io.EOF {
+			return "", errors.New("unexpected end of input")
+		}
+		return "", err
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+// resolveRelease accepts a list number or a tag name and returns the tag, or "" if unknown.
+func (w *initWizard) resolveRelease(answer string) string {
+	if i, err := strconv.Atoi(answer); err == nil {
+		if i >= 1 && i <= len(w.releases) && i <= maxWizardReleases {
+			return w.releases[i-1].TagName
+		}
+		return ""
+	}
+	for _, r := range w.releases {
+		if r.TagName == answer || r.TagName == "v"+answer {
+			return r.TagName
+		}
+	}
+	return ""
+}
+
+// ftcSeason returns the starting year of the FTC season in progress; seasons kick off in September.
+func ftcSeason(t time.Time) int {
+	if t.Month() >= time.September {
+		return t.Year()
+	}
+	return t.Year() - 1
+}
+
+// suggestProjectName builds a default project name like "2025-12345".
+func suggestProjectName(team, season string) string {
+	if team == "" {
+		return season + "-robot"
+	}
+	return season + "-" + team
+}
+
+// parseLibraryList validates a comma separated list of library specs.
+func parseLibraryList(s string) ([]string, error) {
+	var libs []string
+	for _, spec := range strings.Split(s, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		if _, err := lookupLibrary(spec); err != nil {
+			return nil, err
+		}
+		libs = append(libs, spec)
+	}
+	return libs, nil
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
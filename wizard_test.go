@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestWizard(input string) (*initWizard, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &initWizard{
+		in:       bufio.NewReader(strings.NewReader(input)),
+		out:      out,
+		releases: []Release{{TagName: "v10.1"}, {TagName: "v10.0"}},
+		now:      time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC),
+	}, out
+}
+
+func TestInitWizard(t *testing.T) {
+	withoutUserLibraries(t)
+	workDir = t.TempDir()
+	input := strings.Join([]string{
+		"2",                         // release
+		"12345",                     // team
+		"",                          // season (default)
+		"",                          // project name (suggested)
+		"git@github.com/team/robot", // remote, corrected
+		"Kotlin",                    // language
+		"bogus",                     // unknown library, asked again
+		"roadrunner, ftclib@2.1.1",  // libraries
+		"",                          // confirm
+	}, "\n") + "\n"
+
+	w, out := newTestWizard(input)
+	opts, err := w.run()
+	if err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	if opts == nil {
+		t.Fatalf("wizard cancelled unexpectedly\n%s", out)
+	}
+	if opts.Version != "v10.0" {
+		t.Errorf("Version = %q, want v10.0", opts.Version)
+	}
+	if opts.ProjectName != "2025-12345" {
+		t.Errorf("ProjectName = %q, want 2025-12345", opts.ProjectName)
+	}
+	if opts.GitURL != "git@github.com:team/robot.git" {
+		t.Errorf("GitURL = %q", opts.GitURL)
+	}
+	if opts.Language != "kotlin" {
+		t.Errorf("Language = %q, want kotlin", opts.Language)
+	}
+	if strings.Join(opts.Libraries, ",") != "roadrunner,ftclib@2.1.1" {
+		t.Errorf("Libraries = %v", opts.Libraries)
+	}
+	if !strings.Contains(out.String(), `unknown library "bogus"`) {
+		t.Errorf("expected unknown library error in output:\n%s", out)
+	}
+}
+
+func TestInitWizard_RemoteCheckAndCancel(t *testing.T) {
+	withoutUserLibraries(t)
+	workDir = t.TempDir()
+	input := strings.Join([]string{
+		"v10.1", "", "", "my-robot",
+		"team/missing", // unreachable, asked again
+		"",             // no remote
+		"", "", "n",
+	}, "\n") + "\n"
+
+	w, out := newTestWizard(input)
+	w.checkRemote = func(string) error { return errors.New("not found") }
+	opts, err := w.run()
+	if err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	if opts != nil {
+		t.Fatalf("expected cancel, got %+v", opts)
+	}
+	if !strings.Contains(out.String(), "Error: not found") {
+		t.Errorf("expected remote check error in output:\n%s", out)
+	}
+}
+
+func TestInitWizard_EOF(t *testing.T) {
+	withoutUserLibraries(t)
+	workDir = t.TempDir()
+	w, _ := newTestWizard("1\n")
+	if _, err := w.run(); err == nil {
+		t.Fatal("expected error on truncated input")
+	}
+}
+
+func TestFtcSeason(t *testing.T) {
+	if got := ftcSeason(time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)); got != 2025 {
+		t.Errorf("March 2026 season = %d, want 2025", got)
+	}
+	if got := ftcSeason(time.Date(2026, time.September, 6, 0, 0, 0, 0, time.UTC)); got != 2026 {
+		t.Errorf("September 2026 season = %d, want 2026", got)
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Workspace is a named work directory from the "workspaces" config section.
+type Workspace struct {
+	Name string
+	Path string
+}
+
+// configuredWorkspaces returns the workspaces from the config, sorted by name.
+// Viper lower-cases keys, so names are case-insensitive.
+func configuredWorkspaces() []Workspace {
+	var list []Workspace
+	for name, p := range viper.GetStringMapString("workspaces") {
+		list = append(list, Workspace{Name: name, Path: p})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// findWorkspace looks up a workspace by name.
+func findWorkspace(name string) (Workspace, bool) {
+	for _, ws := range configuredWorkspaces() {
+		if ws.Name == strings.ToLower(name) {
+			return ws, true
+		}
+	}
+	return Workspace{}, false
+}
+
+// resolveWorkDir picks the directory commands operate on: an explicit --work-dir wins,
+// then the active workspace (--workspace or the "workspace" key), then work_dir.
+func resolveWorkDir(workDirFlagSet bool) (string, error) {
+	if workDirFlagSet {
+		return viper.GetString("work_dir"), nil
+	}
+	if name := viper.GetString("workspace"); name != "" {
+		ws, ok := findWorkspace(name)
+		if !ok {
+			return "", fmt.Errorf("workspace %q is not configured", name)
+		}
+		return ws.Path, nil
+	}
+	return viper.GetString("work_dir"), nil
+}
+
+// allWorkspaceDirs returns every configured workspace plus work_dir, if it exists and is not already one of them.
+func allWorkspaceDirs() []Workspace {
+	list := configuredWorkspaces()
+	def := viper.GetString("work_dir")
+	if _, err := os.Stat(def); err != nil {
+		return list
+	}
+	for _, ws := range list {
+		if filepath.Clean(ws.Path) == filepath.Clean(def) {
+			return list
+		}
+	}
+	return append([]Workspace{{Name: "(work_dir)", Path: def}}, list...)
+}
+
+// workspace: manage named work directories
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manage named workspaces (work directories)",
+}
+
+var workspaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured workspaces",
+	Run: func(cmd *cobra.Command, args []string) {
+		list := configuredWorkspaces()
+		if len(list) == 0 {
+			fmt.Println("No workspaces configured. Add one with 'ftc-helper workspace add <name> <path>'.")
+			fmt.Println("Using work_dir:", viper.GetString("work_dir"))
+			return
+		}
+
+		active := strings.ToLower(viper.GetString("workspace"))
+		for _, ws := range list {
+			mark := " "
+			if ws.Name == active {
+				mark = "*"
+			}
+			count := "?"
+			if projects, err := findProjects(ws.Path); err == nil {
+				count = fmt.Sprint(len(projects))
+			}
+			fmt.Printf("%s %-16s %-4s %s\n", mark, ws.Name, count, ws.Path)
+		}
+	},
+}
+
+var workspaceAddCmd = &cobra.Command{
+	Use:   "add [name] [path]",
+	Short: "Add a named workspace",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.ToLower(args[0])
+		use, _ := cmd.Flags().GetBool("use")
+
+		if strings.ContainsAny(name, ". ") {
+			fmt.Println("Workspace names cannot contain dots or spaces.")
+			return
+		}
+
+		p, err := (ConfigKey{Key: "workspaces." + name, Type: "path"}).parseValue(args[1])
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		abs, err := filepath.Abs(p.(string))
+		if err != nil {
+			fmt.Println("Error resolving path:", err)
+			return
+		}
+		if err := os.MkdirAll(abs, 0755); err != nil {
+			fmt.Println("Error creating workspace directory:", err)
+			return
+		}
+
+		if _, err := saveConfigValue("workspaces."+name, abs); err != nil {
+			fmt.Println("Error saving config:", err)
+			return
+		}
+		fmt.Printf("Added workspace %s -> %s\n", name, abs)
+
+		if use {
+			if _, err := saveConfigValue("workspace", name); err != nil {
+				fmt.Println("Error saving config:", err)
+				return
+			}
+			fmt.Println("Now using workspace", name)
+		}
+	},
+}
+
+var workspaceUseCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Make a workspace the default for all commands",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ws, ok := findWorkspace(args[0])
+		if !ok {
+			fmt.Printf("Workspace %q is not configured. See 'ftc-helper workspace list'.\n", args[0])
+			return
+		}
+		if _, err := saveConfigValue("workspace", ws.Name); err != nil {
+			fmt.Println("Error saving config:", err)
+			return
+		}
+		fmt.Printf("Now using workspace %s (%s)\n", ws.Name, ws.Path)
+	},
+}
+
+// removeWorkspace deletes a workspace from the config file, and makes none active if it was the
+// active one. The directory and its projects are left alone.
+func removeWorkspace(name string) (wasActive bool, err error) {
+	p := configFilePath()
+	m, err := readConfigFile(p)
+	if err != nil {
+		return false, err
+	}
+	if !unsetConfigKey(m, "workspaces."+name) {
+		return false, fmt.Errorf("workspace %q is not configured in %s", name, p)
+	}
+	if active, _ := m["workspace"].(string); strings.ToLower(active) == name {
+		unsetConfigKey(m, "workspace")
+		wasActive = true
+	}
+	if err := writeConfigFile(p, m); err != nil {
+		return false, err
+	}
+	if wasActive {
+		viper.Set("workspace", "")
+	}
+	return wasActive, nil
+}
+
+var workspaceRemoveCmd = &cobra.Command{
+	Use:   "remove [name]",
+	Short: "Remove a named workspace (the directory is kept)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.ToLower(args[0])
+		wasActive, err := removeWorkspace(name)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Printf("Removed workspace %s. Its directory and projects are untouched.\n", name)
+		if wasActive {
+			fmt.Println("It was the active workspace; commands use work_dir again.")
+		}
+	},
+}
+
+func init() {
+	workspaceAddCmd.Flags().Bool("use", false, "Also make the new workspace active")
+	workspaceCmd.AddCommand(workspaceListCmd)
+	workspaceCmd.AddCommand(workspaceAddCmd)
+	workspaceCmd.AddCommand(workspaceUseCmd)
+	workspaceCmd.AddCommand(workspaceRemoveCmd)
+}
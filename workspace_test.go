@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestResolveWorkDir(t *testing.T) {
+	viper.Set("work_dir", "/default")
+	viper.Set("workspaces", map[string]interface{}{"team1": "/ws/team1", "archive": "/ws/archive"})
+	defer func() {
+		viper.Set("work_dir", "")
+		viper.Set("workspaces", nil)
+		viper.Set("workspace", "")
+	}()
+
+	cases := []struct {
+		active  string
+		flagSet bool
+		want    string
+		wantErr bool
+	}{
+		{"", false, "/default", false},
+		{"team1", false, "/ws/team1", false},
+		{"Archive", false, "/ws/archive", false},
+		{"team1", true, "/default", false},
+		{"missing", false, "", true},
+	}
+	for _, c := range cases {
+		viper.Set("workspace", c.active)
+		got, err := resolveWorkDir(c.flagSet)
+		if c.wantErr {
+			if err == nil {
+				t.Fatalf("expected error for workspace %q", c.active)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for workspace %q: %v", c.active, err)
+		}
+		if got != c.want {
+			t.Fatalf("resolveWorkDir(%q, %v) = %q, want %q", c.active, c.flagSet, got, c.want)
+		}
+	}
+}
+
+func TestFindProjects(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "robot", "TeamCode", "src", "main", "java", "org", "firstinspires", "ftc", "teamcode"), 0755)
+	os.MkdirAll(filepath.Join(dir, "not-a-project"), 0755)
+
+	projects, err := findProjects(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 1 || projects[0] != "robot" {
+		t.Fatalf("findProjects = %v, want [robot]", projects)
+	}
+}
+
+func TestWorkspaceEnv(t *testing.T) {
+	// A fresh instance, as earlier tests leave overrides on the global one.
+	v := viper.New()
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(envKeyReplacer)
+	t.Setenv("WORKSPACE", "/var/lib/jenkins/job")
+	if got := v.GetString("workspace"); got != "" {
+		t.Errorf("workspace = %q from WORKSPACE, want it ignored", got)
+	}
+	t.Setenv("FTC_HELPER_WORKSPACE", "team1")
+	if got := v.GetString("workspace"); got != "team1" {
+		t.Errorf("workspace = %q, want team1 from FTC_HELPER_WORKSPACE", got)
+	}
+	if got := (ConfigKey{Key: "workspace"}).envName(); got != "FTC_HELPER_WORKSPACE" {
+		t.Errorf("envName = %q", got)
+	}
+}
+
+func TestRemoveWorkspace(t *testing.T) {
+	cfgFile = filepath.Join(t.TempDir(), "config.yaml")
+	defer func() {
+		cfgFile = ""
+		viper.Set("workspace", "")
+	}()
+	os.WriteFile(cfgFile, []byte("workspace: team1\nworkspaces:\n  team1: /ws/team1\n  archive: /ws/archive\n"), 0644)
+
+	wasActive, err := removeWorkspace("team1")
+	if err != nil || !wasActive {
+		t.Fatalf("removeWorkspace = %v, %v", wasActive, err)
+	}
+	b, _ := os.ReadFile(cfgFile)
+	if want := "workspaces:\n  archive: /ws/archive\n"; string(b) != want {
+		t.Errorf("config file =\n%s\nwant\n%s", b, want)
+	}
+	if _, err := removeWorkspace("team1"); err == nil {
+		t.Error("expected an error removing a missing workspace")
+	}
+}